@@ -0,0 +1,275 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+// inspect_segment prints the contents of a FST segment persisted to a file set volume
+// for debugging purposes.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/generated/proto/fswriter"
+	"github.com/m3db/m3ninx/idx"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/segment/fs"
+	"github.com/m3db/m3ninx/persist"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/executor"
+)
+
+const usage = `Usage: inspect_segment -path DIR [-segment N] COMMAND [ARGS]
+
+Commands:
+  info                 print the segment's type, versions and metadata
+  fields               list the segment's fields with their number of terms
+  terms FIELD          list the terms of a field with the number of documents containing them
+  doc POSTINGS_ID      print the document with the given postings ID
+  docid ID             print the document with the given document ID
+  query QUERY          print the documents matching a query, e.g. 'city:nyc AND NOT env:dev'
+
+Flags:
+`
+
+var (
+	pathArg     = flag.String("path", "", "path to the file set volume directory")
+	segmentArg  = flag.Int("segment", 0, "index of the segment within the volume")
+	limitArg    = flag.Int("limit", 0, "maximum number of documents returned by a query, unbounded if zero")
+	verifyArg   = flag.Bool("verify", false, "verify the integrity of the segment before inspecting it")
+	errNotFound = errors.New("document not found")
+)
+
+type command struct {
+	args int
+	run  func(fileset persist.IndexSegmentFileSet, seg fs.Segment, args []string) error
+}
+
+var commands = map[string]command{
+	"info":   {args: 0, run: printInfo},
+	"fields": {args: 0, run: printFields},
+	"terms":  {args: 1, run: printTerms},
+	"doc":    {args: 1, run: printDoc},
+	"docid":  {args: 1, run: printDocID},
+	"query":  {args: 1, run: printQuery},
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if *pathArg == "" || len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok || len(args)-1 != cmd.args {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fileset, seg, err := openSegment(*pathArg, *segmentArg)
+	if err != nil {
+		log.Fatalf("unable to open segment: %v", err)
+	}
+	defer seg.Close()
+
+	if *verifyArg {
+		if err := seg.Verify(); err != nil {
+			log.Fatalf("segment failed verification: %v", err)
+		}
+	}
+
+	if err := cmd.run(fileset, seg, args[1:]); err != nil {
+		log.Fatalf("unable to run %s: %v", args[0], err)
+	}
+}
+
+// openSegment opens the segment at the given index within the volume. The remaining
+// segments in the volume are closed.
+func openSegment(dir string, idx int) (persist.IndexSegmentFileSet, fs.Segment, error) {
+	reader, err := persist.NewFileSetReader(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n := reader.SegmentFileSets(); idx < 0 || idx >= n {
+		return nil, nil, fmt.Errorf("segment %d out of range, volume contains %d segments", idx, n)
+	}
+
+	for i := 0; ; i++ {
+		fileset, err := reader.ReadSegmentFileSet()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if i < idx {
+			for _, f := range fileset.Files() {
+				f.Close()
+			}
+			continue
+		}
+
+		seg, err := persist.NewSegment(fileset, fs.NewSegmentOpts{
+			PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return fileset, seg, nil
+	}
+}
+
+func printInfo(fileset persist.IndexSegmentFileSet, seg fs.Segment, _ []string) error {
+	var metadata fswriter.Metadata
+	if err := metadata.Unmarshal(fileset.SegmentMetadata()); err != nil {
+		return err
+	}
+
+	fmt.Printf("type: %s\n", fileset.SegmentType())
+	fmt.Printf("version: %d.%d\n", fileset.MajorVersion(), fileset.MinorVersion())
+	fmt.Printf("documents: %d\n", seg.Size())
+	fmt.Printf("postings format: %s\n", metadata.PostingsFormat)
+	fmt.Printf("field postings: %t\n", metadata.FieldPostings)
+	fmt.Printf("doc frequencies: %t\n", metadata.DocFrequencies)
+	fmt.Printf("file digests: %t\n", metadata.FileDigests)
+	return nil
+}
+
+func printFields(_ persist.IndexSegmentFileSet, seg fs.Segment, _ []string) error {
+	fields, err := seg.Fields()
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		terms, err := seg.Terms(field)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%d\n", field, len(terms))
+	}
+	return nil
+}
+
+func printTerms(_ persist.IndexSegmentFileSet, seg fs.Segment, args []string) error {
+	iter, err := seg.FieldTerms([]byte(args[0]))
+	if err != nil {
+		return err
+	}
+
+	for iter.Next() {
+		term, pl := iter.Current()
+		fmt.Printf("%s\t%d\n", term, pl.Len())
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	return iter.Close()
+}
+
+func printDoc(_ persist.IndexSegmentFileSet, seg fs.Segment, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid postings ID %q: %v", args[0], err)
+	}
+
+	d, err := seg.Doc(postings.ID(id))
+	if err != nil {
+		return err
+	}
+	fmt.Println(d)
+	return nil
+}
+
+func printDocID(_ persist.IndexSegmentFileSet, seg fs.Segment, args []string) error {
+	pl, err := seg.MatchTerm(doc.IDReservedFieldName, []byte(args[0]))
+	if err != nil {
+		return err
+	}
+
+	iter := pl.Iterator()
+	if !iter.Next() {
+		iter.Close()
+		return errNotFound
+	}
+	id := iter.Current()
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	d, err := seg.Doc(id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d\t%v\n", id, d)
+	return nil
+}
+
+func printQuery(_ persist.IndexSegmentFileSet, seg fs.Segment, args []string) error {
+	q, err := idx.ParseQuery(args[0])
+	if err != nil {
+		return err
+	}
+
+	r, err := seg.Reader()
+	if err != nil {
+		return err
+	}
+
+	exec := executor.NewExecutor(index.Readers{r}, executor.NewOptions())
+	defer exec.Close()
+
+	iter, err := exec.Execute(q.SearchQuery(), search.ExecuteOptions{Limit: *limitArg})
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for iter.Next() {
+		fmt.Println(iter.Current())
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	if iter.LimitExceeded() {
+		fmt.Fprintf(os.Stderr, "%d documents returned, limit exceeded\n", n)
+	} else {
+		fmt.Fprintf(os.Stderr, "%d documents returned\n", n)
+	}
+	return nil
+}
@@ -118,6 +118,9 @@ func (PostingsFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptorFs
 type Metadata struct {
 	PostingsFormat PostingsFormat `protobuf:"varint,1,opt,name=postingsFormat,proto3,enum=fswriter.PostingsFormat" json:"postingsFormat,omitempty"`
 	NumDocs        int64          `protobuf:"varint,2,opt,name=numDocs,proto3" json:"numDocs,omitempty"`
+	FieldPostings  bool           `protobuf:"varint,3,opt,name=fieldPostings,proto3" json:"fieldPostings,omitempty"`
+	DocFrequencies bool           `protobuf:"varint,4,opt,name=docFrequencies,proto3" json:"docFrequencies,omitempty"`
+	FileDigests    bool           `protobuf:"varint,5,opt,name=fileDigests,proto3" json:"fileDigests,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -139,6 +142,27 @@ func (m *Metadata) GetNumDocs() int64 {
 	return 0
 }
 
+func (m *Metadata) GetFieldPostings() bool {
+	if m != nil {
+		return m.FieldPostings
+	}
+	return false
+}
+
+func (m *Metadata) GetDocFrequencies() bool {
+	if m != nil {
+		return m.DocFrequencies
+	}
+	return false
+}
+
+func (m *Metadata) GetFileDigests() bool {
+	if m != nil {
+		return m.FileDigests
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Metadata)(nil), "fswriter.Metadata")
 	proto.RegisterEnum("fswriter.SegmentType", SegmentType_name, SegmentType_value)
@@ -170,6 +194,36 @@ func (m *Metadata) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintFswriter(dAtA, i, uint64(m.NumDocs))
 	}
+	if m.FieldPostings {
+		dAtA[i] = 0x18
+		i++
+		if m.FieldPostings {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.DocFrequencies {
+		dAtA[i] = 0x20
+		i++
+		if m.DocFrequencies {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.FileDigests {
+		dAtA[i] = 0x28
+		i++
+		if m.FileDigests {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
 	return i, nil
 }
 
@@ -191,6 +245,15 @@ func (m *Metadata) Size() (n int) {
 	if m.NumDocs != 0 {
 		n += 1 + sovFswriter(uint64(m.NumDocs))
 	}
+	if m.FieldPostings {
+		n += 2
+	}
+	if m.DocFrequencies {
+		n += 2
+	}
+	if m.FileDigests {
+		n += 2
+	}
 	return n
 }
 
@@ -274,6 +337,66 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FieldPostings", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFswriter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FieldPostings = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DocFrequencies", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFswriter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DocFrequencies = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FileDigests", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowFswriter
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FileDigests = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipFswriter(dAtA[iNdEx:])
@@ -403,23 +526,27 @@ var (
 func init() { proto.RegisterFile("fswriter.proto", fileDescriptorFswriter) }
 
 var fileDescriptorFswriter = []byte{
-	// 287 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x90, 0xc1, 0x4a, 0xf3, 0x40,
-	0x10, 0xc7, 0xb3, 0xed, 0xc7, 0x67, 0x1d, 0xe9, 0xba, 0xae, 0x1e, 0x72, 0x90, 0x50, 0xf4, 0x52,
-	0x7a, 0x28, 0xa8, 0x2f, 0x60, 0x34, 0xbb, 0x25, 0xd0, 0x34, 0x21, 0xbb, 0x8a, 0x9e, 0x42, 0xd4,
-	0x6d, 0x09, 0x98, 0x6c, 0x48, 0x56, 0xc4, 0x37, 0xf1, 0x91, 0x3c, 0xfa, 0x08, 0x12, 0x5f, 0x44,
-	0x52, 0xda, 0x4a, 0x3d, 0xfe, 0xfe, 0xf3, 0x9b, 0x19, 0x66, 0x00, 0xcf, 0xeb, 0xd7, 0x2a, 0x33,
-	0xaa, 0x1a, 0x97, 0x95, 0x36, 0x9a, 0xf6, 0xd6, 0x7c, 0x32, 0x87, 0x5e, 0xa0, 0x4c, 0xfa, 0x94,
-	0x9a, 0x94, 0x5e, 0x02, 0x2e, 0x75, 0x6d, 0xb2, 0x62, 0x51, 0x73, 0x5d, 0xe5, 0xa9, 0xb1, 0xd1,
-	0x00, 0x0d, 0xf1, 0xb9, 0x3d, 0xde, 0xb4, 0x47, 0x5b, 0xf5, 0xf8, 0x8f, 0x4f, 0x6d, 0xd8, 0x29,
-	0x5e, 0x72, 0x4f, 0x3f, 0xd6, 0x76, 0x67, 0x80, 0x86, 0xdd, 0x78, 0x8d, 0xa3, 0x53, 0xd8, 0x13,
-	0x6a, 0x91, 0xab, 0xc2, 0xc8, 0xb7, 0x52, 0xd1, 0x23, 0x20, 0x5c, 0xc8, 0x44, 0xb0, 0x49, 0xc0,
-	0x66, 0x32, 0x91, 0xf7, 0x11, 0x23, 0xd6, 0x48, 0x03, 0xe5, 0x42, 0xae, 0x3c, 0x9e, 0x3d, 0xab,
-	0xa5, 0x7b, 0x08, 0xfb, 0x5e, 0x78, 0x7d, 0xd3, 0x8a, 0x22, 0xf1, 0x67, 0x1e, 0xbb, 0x23, 0x16,
-	0xa5, 0x80, 0x7f, 0x43, 0xcf, 0x95, 0x2e, 0x41, 0xf4, 0x00, 0xfa, 0x51, 0x28, 0xa4, 0x3f, 0x9b,
-	0xac, 0xa2, 0x0e, 0xed, 0xc3, 0x6e, 0xbb, 0x47, 0xb2, 0x38, 0x10, 0xa4, 0x4b, 0x31, 0x40, 0x8b,
-	0xdc, 0x67, 0x53, 0x4f, 0x90, 0x7f, 0xa3, 0x31, 0xe0, 0xed, 0x8b, 0xe8, 0x31, 0xd8, 0x91, 0x3f,
-	0x0d, 0x85, 0x7b, 0x7b, 0x96, 0x6c, 0x86, 0xf1, 0x30, 0x0e, 0x5c, 0x49, 0xac, 0x2b, 0xf2, 0xd1,
-	0x38, 0xe8, 0xb3, 0x71, 0xd0, 0x57, 0xe3, 0xa0, 0xf7, 0x6f, 0xc7, 0x7a, 0xf8, 0xbf, 0x7c, 0xe8,
-	0xc5, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x33, 0x3f, 0x16, 0x2d, 0x62, 0x01, 0x00, 0x00,
+	// 339 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xc1, 0x4e, 0xea, 0x40,
+	0x14, 0x86, 0x3b, 0xc0, 0xbd, 0x97, 0x7b, 0x08, 0x75, 0x1c, 0x5d, 0xcc, 0xc2, 0x34, 0x8d, 0x1a,
+	0x43, 0x58, 0x90, 0xa8, 0x2f, 0x60, 0xb5, 0x2d, 0x69, 0x42, 0x69, 0xd3, 0x19, 0x8d, 0xae, 0x9a,
+	0x0a, 0x03, 0x69, 0x02, 0x2d, 0x76, 0x86, 0x18, 0xdf, 0xc4, 0x47, 0x72, 0xe9, 0xca, 0xb5, 0xc1,
+	0x17, 0x31, 0x34, 0x80, 0xc2, 0xf2, 0xff, 0xe6, 0x9b, 0xff, 0x24, 0xe7, 0x80, 0x3e, 0x92, 0xcf,
+	0x45, 0xaa, 0x44, 0xd1, 0x99, 0x15, 0xb9, 0xca, 0x49, 0x7d, 0x9d, 0x8f, 0x3f, 0x10, 0xd4, 0x7d,
+	0xa1, 0x92, 0x61, 0xa2, 0x12, 0x72, 0x05, 0xfa, 0x2c, 0x97, 0x2a, 0xcd, 0xc6, 0xd2, 0xcd, 0x8b,
+	0x69, 0xa2, 0x28, 0x32, 0x51, 0x4b, 0xbf, 0xa0, 0x9d, 0xcd, 0xff, 0x70, 0xeb, 0x3d, 0xda, 0xf1,
+	0x09, 0x85, 0x7f, 0xd9, 0x7c, 0x6a, 0xe7, 0x03, 0x49, 0x2b, 0x26, 0x6a, 0x55, 0xa3, 0x75, 0x24,
+	0xa7, 0xd0, 0x1c, 0xa5, 0x62, 0x32, 0x5c, 0x17, 0xd0, 0xaa, 0x89, 0x5a, 0xf5, 0x68, 0x1b, 0x92,
+	0x33, 0xd0, 0x87, 0xf9, 0xc0, 0x2d, 0xc4, 0xd3, 0x5c, 0x64, 0x83, 0x54, 0x48, 0x5a, 0x2b, 0xb5,
+	0x1d, 0x4a, 0x4c, 0x68, 0x8c, 0xd2, 0x89, 0xb0, 0xd3, 0xb1, 0x90, 0x4a, 0xd2, 0x3f, 0xa5, 0xf4,
+	0x1b, 0xb5, 0x4f, 0xa0, 0xc1, 0xc4, 0x78, 0x2a, 0x32, 0xc5, 0x5f, 0x66, 0x82, 0x1c, 0x02, 0x76,
+	0x19, 0x8f, 0x99, 0xd3, 0xf5, 0x9d, 0x3e, 0x8f, 0xf9, 0x43, 0xe8, 0x60, 0xad, 0x9d, 0x03, 0x71,
+	0x19, 0x5f, 0x79, 0x6e, 0x3a, 0x11, 0xa5, 0x7b, 0x00, 0x7b, 0x76, 0x70, 0x73, 0xbb, 0x14, 0x59,
+	0xec, 0xf5, 0x6d, 0xe7, 0x1e, 0x6b, 0x84, 0x80, 0xfe, 0x03, 0x6d, 0x8b, 0x5b, 0x18, 0x91, 0x7d,
+	0x68, 0x86, 0x01, 0xe3, 0x5e, 0xbf, 0xbb, 0x42, 0x15, 0xd2, 0x84, 0xff, 0xcb, 0x39, 0xdc, 0x89,
+	0x7c, 0x86, 0xab, 0x44, 0x07, 0x58, 0x46, 0xd7, 0x73, 0x7a, 0x36, 0xc3, 0xb5, 0x76, 0x07, 0xf4,
+	0xed, 0x0d, 0x92, 0x23, 0xa0, 0xa1, 0xd7, 0x0b, 0x98, 0x75, 0x77, 0x1e, 0x6f, 0xca, 0xdc, 0x20,
+	0xf2, 0x2d, 0x8e, 0xb5, 0x6b, 0xfc, 0xb6, 0x30, 0xd0, 0xfb, 0xc2, 0x40, 0x9f, 0x0b, 0x03, 0xbd,
+	0x7e, 0x19, 0xda, 0xe3, 0xdf, 0xf2, 0x82, 0x97, 0xdf, 0x03, 0x00, 0x43, 0x59, 0xa9, 0xa1, 0xd3,
+	0x01, 0x00, 0x00,
 }
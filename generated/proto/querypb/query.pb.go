@@ -30,6 +30,10 @@
 	It has these top-level messages:
 		TermQuery
 		RegexpQuery
+		PrefixQuery
+		TermRangeQuery
+		FieldQuery
+		TermsQuery
 		NegationQuery
 		ConjunctionQuery
 		DisjunctionQuery
@@ -102,6 +106,118 @@ func (m *RegexpQuery) GetRegexp() []byte {
 	return nil
 }
 
+type PrefixQuery struct {
+	Field  []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Prefix []byte `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (m *PrefixQuery) Reset()                    { *m = PrefixQuery{} }
+func (m *PrefixQuery) String() string            { return proto.CompactTextString(m) }
+func (*PrefixQuery) ProtoMessage()               {}
+func (*PrefixQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{2} }
+
+func (m *PrefixQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *PrefixQuery) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+type TermRangeQuery struct {
+	Field        []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Min          []byte `protobuf:"bytes,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max          []byte `protobuf:"bytes,3,opt,name=max,proto3" json:"max,omitempty"`
+	MinInclusive bool   `protobuf:"varint,4,opt,name=min_inclusive,json=minInclusive,proto3" json:"min_inclusive,omitempty"`
+	MaxInclusive bool   `protobuf:"varint,5,opt,name=max_inclusive,json=maxInclusive,proto3" json:"max_inclusive,omitempty"`
+}
+
+func (m *TermRangeQuery) Reset()                    { *m = TermRangeQuery{} }
+func (m *TermRangeQuery) String() string            { return proto.CompactTextString(m) }
+func (*TermRangeQuery) ProtoMessage()               {}
+func (*TermRangeQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{3} }
+
+func (m *TermRangeQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *TermRangeQuery) GetMin() []byte {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *TermRangeQuery) GetMax() []byte {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+func (m *TermRangeQuery) GetMinInclusive() bool {
+	if m != nil {
+		return m.MinInclusive
+	}
+	return false
+}
+
+func (m *TermRangeQuery) GetMaxInclusive() bool {
+	if m != nil {
+		return m.MaxInclusive
+	}
+	return false
+}
+
+type FieldQuery struct {
+	Field []byte `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+}
+
+func (m *FieldQuery) Reset()                    { *m = FieldQuery{} }
+func (m *FieldQuery) String() string            { return proto.CompactTextString(m) }
+func (*FieldQuery) ProtoMessage()               {}
+func (*FieldQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{4} }
+
+func (m *FieldQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+type TermsQuery struct {
+	Field []byte   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Terms [][]byte `protobuf:"bytes,2,rep,name=terms" json:"terms,omitempty"`
+}
+
+func (m *TermsQuery) Reset()                    { *m = TermsQuery{} }
+func (m *TermsQuery) String() string            { return proto.CompactTextString(m) }
+func (*TermsQuery) ProtoMessage()               {}
+func (*TermsQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+
+func (m *TermsQuery) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *TermsQuery) GetTerms() [][]byte {
+	if m != nil {
+		return m.Terms
+	}
+	return nil
+}
+
 type NegationQuery struct {
 	Query *Query `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
 }
@@ -109,7 +225,7 @@ type NegationQuery struct {
 func (m *NegationQuery) Reset()                    { *m = NegationQuery{} }
 func (m *NegationQuery) String() string            { return proto.CompactTextString(m) }
 func (*NegationQuery) ProtoMessage()               {}
-func (*NegationQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{2} }
+func (*NegationQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{6} }
 
 func (m *NegationQuery) GetQuery() *Query {
 	if m != nil {
@@ -125,7 +241,7 @@ type ConjunctionQuery struct {
 func (m *ConjunctionQuery) Reset()                    { *m = ConjunctionQuery{} }
 func (m *ConjunctionQuery) String() string            { return proto.CompactTextString(m) }
 func (*ConjunctionQuery) ProtoMessage()               {}
-func (*ConjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{3} }
+func (*ConjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{7} }
 
 func (m *ConjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -141,7 +257,7 @@ type DisjunctionQuery struct {
 func (m *DisjunctionQuery) Reset()                    { *m = DisjunctionQuery{} }
 func (m *DisjunctionQuery) String() string            { return proto.CompactTextString(m) }
 func (*DisjunctionQuery) ProtoMessage()               {}
-func (*DisjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{4} }
+func (*DisjunctionQuery) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{8} }
 
 func (m *DisjunctionQuery) GetQueries() []*Query {
 	if m != nil {
@@ -157,13 +273,17 @@ type Query struct {
 	//	*Query_Negation
 	//	*Query_Conjunction
 	//	*Query_Disjunction
+	//	*Query_Prefix
+	//	*Query_TermRange
+	//	*Query_Field
+	//	*Query_Terms
 	Query isQuery_Query `protobuf_oneof:"query"`
 }
 
 func (m *Query) Reset()                    { *m = Query{} }
 func (m *Query) String() string            { return proto.CompactTextString(m) }
 func (*Query) ProtoMessage()               {}
-func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{5} }
+func (*Query) Descriptor() ([]byte, []int) { return fileDescriptorQuery, []int{9} }
 
 type isQuery_Query interface {
 	isQuery_Query()
@@ -186,12 +306,28 @@ type Query_Conjunction struct {
 type Query_Disjunction struct {
 	Disjunction *DisjunctionQuery `protobuf:"bytes,5,opt,name=disjunction,oneof"`
 }
+type Query_Prefix struct {
+	Prefix *PrefixQuery `protobuf:"bytes,6,opt,name=prefix,oneof"`
+}
+type Query_TermRange struct {
+	TermRange *TermRangeQuery `protobuf:"bytes,7,opt,name=term_range,json=termRange,oneof"`
+}
+type Query_Field struct {
+	Field *FieldQuery `protobuf:"bytes,8,opt,name=field,oneof"`
+}
+type Query_Terms struct {
+	Terms *TermsQuery `protobuf:"bytes,9,opt,name=terms,oneof"`
+}
 
 func (*Query_Term) isQuery_Query()        {}
 func (*Query_Regexp) isQuery_Query()      {}
 func (*Query_Negation) isQuery_Query()    {}
 func (*Query_Conjunction) isQuery_Query() {}
 func (*Query_Disjunction) isQuery_Query() {}
+func (*Query_Prefix) isQuery_Query()      {}
+func (*Query_TermRange) isQuery_Query()   {}
+func (*Query_Field) isQuery_Query()       {}
+func (*Query_Terms) isQuery_Query()       {}
 
 func (m *Query) GetQuery() isQuery_Query {
 	if m != nil {
@@ -235,6 +371,34 @@ func (m *Query) GetDisjunction() *DisjunctionQuery {
 	return nil
 }
 
+func (m *Query) GetPrefix() *PrefixQuery {
+	if x, ok := m.GetQuery().(*Query_Prefix); ok {
+		return x.Prefix
+	}
+	return nil
+}
+
+func (m *Query) GetTermRange() *TermRangeQuery {
+	if x, ok := m.GetQuery().(*Query_TermRange); ok {
+		return x.TermRange
+	}
+	return nil
+}
+
+func (m *Query) GetField() *FieldQuery {
+	if x, ok := m.GetQuery().(*Query_Field); ok {
+		return x.Field
+	}
+	return nil
+}
+
+func (m *Query) GetTerms() *TermsQuery {
+	if x, ok := m.GetQuery().(*Query_Terms); ok {
+		return x.Terms
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Query_OneofMarshaler, _Query_OneofUnmarshaler, _Query_OneofSizer, []interface{}{
@@ -243,6 +407,10 @@ func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Query_Negation)(nil),
 		(*Query_Conjunction)(nil),
 		(*Query_Disjunction)(nil),
+		(*Query_Prefix)(nil),
+		(*Query_TermRange)(nil),
+		(*Query_Field)(nil),
+		(*Query_Terms)(nil),
 	}
 }
 
@@ -275,6 +443,26 @@ func _Query_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Disjunction); err != nil {
 			return err
 		}
+	case *Query_Prefix:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Prefix); err != nil {
+			return err
+		}
+	case *Query_TermRange:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.TermRange); err != nil {
+			return err
+		}
+	case *Query_Field:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Field); err != nil {
+			return err
+		}
+	case *Query_Terms:
+		_ = b.EncodeVarint(9<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Terms); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Query.Query has unexpected type %T", x)
@@ -325,6 +513,38 @@ func _Query_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		err := b.DecodeMessage(msg)
 		m.Query = &Query_Disjunction{msg}
 		return true, err
+	case 6: // query.prefix
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PrefixQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Prefix{msg}
+		return true, err
+	case 7: // query.term_range
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(TermRangeQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_TermRange{msg}
+		return true, err
+	case 8: // query.field
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FieldQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Field{msg}
+		return true, err
+	case 9: // query.terms
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(TermsQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_Terms{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -359,6 +579,26 @@ func _Query_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Query_Prefix:
+		s := proto.Size(x.Prefix)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_TermRange:
+		s := proto.Size(x.TermRange)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_Field:
+		s := proto.Size(x.Field)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_Terms:
+		s := proto.Size(x.Terms)
+		n += proto.SizeVarint(9<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -369,6 +609,10 @@ func _Query_OneofSizer(msg proto.Message) (n int) {
 func init() {
 	proto.RegisterType((*TermQuery)(nil), "query.TermQuery")
 	proto.RegisterType((*RegexpQuery)(nil), "query.RegexpQuery")
+	proto.RegisterType((*PrefixQuery)(nil), "query.PrefixQuery")
+	proto.RegisterType((*TermRangeQuery)(nil), "query.TermRangeQuery")
+	proto.RegisterType((*FieldQuery)(nil), "query.FieldQuery")
+	proto.RegisterType((*TermsQuery)(nil), "query.TermsQuery")
 	proto.RegisterType((*NegationQuery)(nil), "query.NegationQuery")
 	proto.RegisterType((*ConjunctionQuery)(nil), "query.ConjunctionQuery")
 	proto.RegisterType((*DisjunctionQuery)(nil), "query.DisjunctionQuery")
@@ -434,6 +678,148 @@ func (m *RegexpQuery) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *PrefixQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrefixQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Prefix) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Prefix)))
+		i += copy(dAtA[i:], m.Prefix)
+	}
+	return i, nil
+}
+
+func (m *TermRangeQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TermRangeQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Min) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Min)))
+		i += copy(dAtA[i:], m.Min)
+	}
+	if len(m.Max) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Max)))
+		i += copy(dAtA[i:], m.Max)
+	}
+	if m.MinInclusive {
+		dAtA[i] = 0x20
+		i++
+		if m.MinInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxInclusive {
+		dAtA[i] = 0x28
+		i++
+		if m.MaxInclusive {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FieldQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FieldQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	return i, nil
+}
+
+func (m *TermsQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TermsQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Field) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Field)))
+		i += copy(dAtA[i:], m.Field)
+	}
+	if len(m.Terms) > 0 {
+		for _, b := range m.Terms {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintQuery(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
 func (m *NegationQuery) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -617,24 +1003,80 @@ func (m *Query_Disjunction) MarshalTo(dAtA []byte) (int, error) {
 	}
 	return i, nil
 }
-func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *Query_Prefix) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Prefix != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Prefix.Size()))
+		n8, err := m.Prefix.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return i, nil
 }
-func (m *TermQuery) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Field)
-	if l > 0 {
-		n += 1 + l + sovQuery(uint64(l))
+func (m *Query_TermRange) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.TermRange != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.TermRange.Size()))
+		n9, err := m.TermRange.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
 	}
-	l = len(m.Term)
-	if l > 0 {
+	return i, nil
+}
+func (m *Query_Field) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Field != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Field.Size()))
+		n10, err := m.Field.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+func (m *Query_Terms) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Terms != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintQuery(dAtA, i, uint64(m.Terms.Size()))
+		n11, err := m.Terms.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	return i, nil
+}
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *TermQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Term)
+	if l > 0 {
 		n += 1 + l + sovQuery(uint64(l))
 	}
 	return n
@@ -654,6 +1096,70 @@ func (m *RegexpQuery) Size() (n int) {
 	return n
 }
 
+func (m *PrefixQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *TermRangeQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Min)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Max)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.MinInclusive {
+		n += 2
+	}
+	if m.MaxInclusive {
+		n += 2
+	}
+	return n
+}
+
+func (m *FieldQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *TermsQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if len(m.Terms) > 0 {
+		for _, b := range m.Terms {
+			l = len(b)
+			n += 1 + l + sovQuery(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *NegationQuery) Size() (n int) {
 	var l int
 	_ = l
@@ -685,78 +1191,633 @@ func (m *DisjunctionQuery) Size() (n int) {
 			n += 1 + l + sovQuery(uint64(l))
 		}
 	}
-	return n
-}
-
-func (m *Query) Size() (n int) {
-	var l int
-	_ = l
-	if m.Query != nil {
-		n += m.Query.Size()
-	}
-	return n
-}
-
-func (m *Query_Term) Size() (n int) {
-	var l int
-	_ = l
-	if m.Term != nil {
-		l = m.Term.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
-func (m *Query_Regexp) Size() (n int) {
-	var l int
-	_ = l
-	if m.Regexp != nil {
-		l = m.Regexp.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
-func (m *Query_Negation) Size() (n int) {
-	var l int
-	_ = l
-	if m.Negation != nil {
-		l = m.Negation.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
-func (m *Query_Conjunction) Size() (n int) {
-	var l int
-	_ = l
-	if m.Conjunction != nil {
-		l = m.Conjunction.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
-func (m *Query_Disjunction) Size() (n int) {
-	var l int
-	_ = l
-	if m.Disjunction != nil {
-		l = m.Disjunction.Size()
-		n += 1 + l + sovQuery(uint64(l))
-	}
-	return n
-}
+	return n
+}
+
+func (m *Query) Size() (n int) {
+	var l int
+	_ = l
+	if m.Query != nil {
+		n += m.Query.Size()
+	}
+	return n
+}
+
+func (m *Query_Term) Size() (n int) {
+	var l int
+	_ = l
+	if m.Term != nil {
+		l = m.Term.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Regexp) Size() (n int) {
+	var l int
+	_ = l
+	if m.Regexp != nil {
+		l = m.Regexp.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Negation) Size() (n int) {
+	var l int
+	_ = l
+	if m.Negation != nil {
+		l = m.Negation.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Conjunction) Size() (n int) {
+	var l int
+	_ = l
+	if m.Conjunction != nil {
+		l = m.Conjunction.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Disjunction) Size() (n int) {
+	var l int
+	_ = l
+	if m.Disjunction != nil {
+		l = m.Disjunction.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Prefix) Size() (n int) {
+	var l int
+	_ = l
+	if m.Prefix != nil {
+		l = m.Prefix.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_TermRange) Size() (n int) {
+	var l int
+	_ = l
+	if m.TermRange != nil {
+		l = m.TermRange.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Field) Size() (n int) {
+	var l int
+	_ = l
+	if m.Field != nil {
+		l = m.Field.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+func (m *Query_Terms) Size() (n int) {
+	var l int
+	_ = l
+	if m.Terms != nil {
+		l = m.Terms.Size()
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *TermQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
+			if m.Term == nil {
+				m.Term = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
+			if m.Regexp == nil {
+				m.Regexp = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrefixQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrefixQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrefixQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TermRangeQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TermRangeQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TermRangeQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = append(m.Field[:0], dAtA[iNdEx:postIndex]...)
+			if m.Field == nil {
+				m.Field = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Min", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Min = append(m.Min[:0], dAtA[iNdEx:postIndex]...)
+			if m.Min == nil {
+				m.Min = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Max = append(m.Max[:0], dAtA[iNdEx:postIndex]...)
+			if m.Max == nil {
+				m.Max = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MinInclusive = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxInclusive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MaxInclusive = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
 
-func sovQuery(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozQuery(x uint64) (n int) {
-	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *TermQuery) Unmarshal(dAtA []byte) error {
+func (m *FieldQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -779,10 +1840,10 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TermQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: FieldQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TermQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FieldQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -816,37 +1877,6 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 				m.Field = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Term", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowQuery
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if byteLen < 0 {
-				return ErrInvalidLengthQuery
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Term = append(m.Term[:0], dAtA[iNdEx:postIndex]...)
-			if m.Term == nil {
-				m.Term = []byte{}
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -868,7 +1898,7 @@ func (m *TermQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
+func (m *TermsQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -891,10 +1921,10 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: RegexpQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: TermsQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: RegexpQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TermsQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -930,7 +1960,7 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Regexp", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Terms", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -954,10 +1984,8 @@ func (m *RegexpQuery) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Regexp = append(m.Regexp[:0], dAtA[iNdEx:postIndex]...)
-			if m.Regexp == nil {
-				m.Regexp = []byte{}
-			}
+			m.Terms = append(m.Terms, make([]byte, postIndex-iNdEx))
+			copy(m.Terms[len(m.Terms)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1414,6 +2442,134 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 			}
 			m.Query = &Query_Disjunction{v}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &PrefixQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Prefix{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TermRange", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &TermRangeQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_TermRange{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FieldQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Field{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Terms", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &TermsQuery{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Query = &Query_Terms{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1543,24 +2699,35 @@ var (
 func init() { proto.RegisterFile("query.proto", fileDescriptorQuery) }
 
 var fileDescriptorQuery = []byte{
-	// 304 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2e, 0x2c, 0x4d, 0x2d,
-	0xaa, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x73, 0x94, 0x4c, 0xb9, 0x38, 0x43,
-	0x52, 0x8b, 0x72, 0x03, 0x41, 0x1c, 0x21, 0x11, 0x2e, 0xd6, 0xb4, 0xcc, 0xd4, 0x9c, 0x14, 0x09,
-	0x46, 0x05, 0x46, 0x0d, 0x9e, 0x20, 0x08, 0x47, 0x48, 0x88, 0x8b, 0xa5, 0x24, 0xb5, 0x28, 0x57,
-	0x82, 0x09, 0x2c, 0x08, 0x66, 0x2b, 0x59, 0x73, 0x71, 0x07, 0xa5, 0xa6, 0xa7, 0x56, 0x14, 0xe0,
-	0xd3, 0x28, 0xc6, 0xc5, 0x56, 0x04, 0x56, 0x04, 0xd5, 0x0a, 0xe5, 0x29, 0x19, 0x73, 0xf1, 0xfa,
-	0xa5, 0xa6, 0x27, 0x96, 0x64, 0xe6, 0xe7, 0x41, 0xb4, 0x2b, 0x71, 0x41, 0x5c, 0x03, 0xd6, 0xce,
-	0x6d, 0xc4, 0xa3, 0x07, 0x71, 0x28, 0x58, 0x32, 0x08, 0xea, 0x50, 0x2b, 0x2e, 0x01, 0xe7, 0xfc,
-	0xbc, 0xac, 0xd2, 0xbc, 0x64, 0x84, 0x3e, 0x35, 0x2e, 0x76, 0x90, 0x64, 0x66, 0x6a, 0xb1, 0x04,
-	0xa3, 0x02, 0x33, 0x86, 0x4e, 0x98, 0x24, 0x48, 0xaf, 0x4b, 0x66, 0x31, 0x79, 0x7a, 0xa7, 0x30,
-	0x71, 0xb1, 0xc2, 0x74, 0x40, 0xc2, 0x01, 0xe2, 0x48, 0x01, 0xa8, 0x72, 0x78, 0xe8, 0x79, 0x30,
-	0x40, 0xc2, 0x46, 0x48, 0x07, 0xc5, 0xdb, 0xdc, 0x46, 0x42, 0x50, 0x95, 0x48, 0x01, 0xe6, 0xc1,
-	0x00, 0x0b, 0x0c, 0x21, 0x23, 0x2e, 0x8e, 0x3c, 0x68, 0x60, 0x48, 0x30, 0x83, 0xd5, 0x8b, 0x40,
-	0xd5, 0xa3, 0x84, 0x91, 0x07, 0x43, 0x10, 0x5c, 0x9d, 0x90, 0x35, 0x17, 0x77, 0x32, 0x22, 0x2c,
-	0x24, 0x58, 0xc0, 0xda, 0xc4, 0xa1, 0xda, 0xd0, 0x43, 0xc9, 0x83, 0x21, 0x08, 0x59, 0x35, 0x48,
-	0x73, 0x0a, 0x22, 0x30, 0x24, 0x58, 0x51, 0x34, 0xa3, 0x07, 0x13, 0x48, 0x33, 0x92, 0x6a, 0x27,
-	0x76, 0x68, 0x4c, 0x39, 0x49, 0x9e, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47,
-	0x72, 0x8c, 0x33, 0x1e, 0xcb, 0x31, 0x44, 0x81, 0x43, 0xac, 0xb2, 0x20, 0x29, 0x89, 0x0d, 0x9c,
-	0xc0, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd0, 0xcb, 0xc7, 0x6d, 0x6f, 0x02, 0x00, 0x00,
+	// 480 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xcf, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0xed, 0xba, 0xce, 0x9f, 0x71, 0x8a, 0xc2, 0x2a, 0x80, 0xb9, 0x44, 0xd1, 0x22, 0xa1,
+	0x56, 0x42, 0x3d, 0xb8, 0x02, 0x21, 0x7a, 0x2b, 0x08, 0x99, 0x0b, 0x82, 0x15, 0x27, 0x2e, 0x95,
+	0x9b, 0x6e, 0xa3, 0x45, 0xf5, 0x26, 0xd8, 0x09, 0x72, 0xdf, 0x83, 0x03, 0x8f, 0xc4, 0xb1, 0x8f,
+	0x80, 0xc2, 0x8b, 0xa0, 0x99, 0xdd, 0x8d, 0xed, 0x22, 0x19, 0x89, 0xdb, 0xce, 0xec, 0xf7, 0x4b,
+	0x66, 0xbf, 0xf9, 0x12, 0x88, 0xbe, 0x6e, 0x64, 0x71, 0x73, 0xbc, 0x2a, 0x96, 0xeb, 0x25, 0x0b,
+	0xa9, 0xe0, 0xcf, 0x61, 0xf8, 0x49, 0x16, 0xf9, 0x47, 0x2c, 0xd8, 0x04, 0xc2, 0x2b, 0x25, 0xaf,
+	0x2f, 0x63, 0x7f, 0xe6, 0x1f, 0x8e, 0x84, 0x29, 0x18, 0x83, 0xfd, 0xb5, 0x2c, 0xf2, 0x78, 0x8f,
+	0x9a, 0x74, 0xe6, 0xa7, 0x10, 0x09, 0xb9, 0x90, 0xd5, 0xaa, 0x0b, 0x7c, 0x08, 0xbd, 0x82, 0x44,
+	0x16, 0xb5, 0x15, 0xc2, 0x1f, 0x0a, 0x79, 0xa5, 0xaa, 0x7f, 0xc0, 0x2b, 0x12, 0x39, 0xd8, 0x54,
+	0xfc, 0xbb, 0x0f, 0xf7, 0x70, 0x62, 0x91, 0xe9, 0x85, 0xec, 0xfa, 0x80, 0x31, 0x04, 0xb9, 0xd2,
+	0x96, 0xc6, 0x23, 0x75, 0xb2, 0x2a, 0x0e, 0x6c, 0x27, 0xab, 0xd8, 0x13, 0x38, 0xc8, 0x95, 0x3e,
+	0x57, 0x7a, 0x7e, 0xbd, 0x29, 0xd5, 0x37, 0x19, 0xef, 0xcf, 0xfc, 0xc3, 0x81, 0x18, 0xe5, 0x4a,
+	0xbf, 0x73, 0x3d, 0x12, 0x65, 0x55, 0x43, 0x14, 0x5a, 0x51, 0x56, 0xed, 0x44, 0x9c, 0x03, 0xbc,
+	0xc5, 0xaf, 0xed, 0x98, 0x88, 0xbf, 0x04, 0xc0, 0xc9, 0xcb, 0xae, 0xa9, 0x27, 0x10, 0xa2, 0xc1,
+	0x65, 0xbc, 0x37, 0x0b, 0xb0, 0x4b, 0x05, 0x3f, 0x81, 0x83, 0xf7, 0x72, 0x91, 0xad, 0xd5, 0x52,
+	0x1b, 0x98, 0x83, 0xd9, 0x1f, 0xc1, 0x51, 0x32, 0x3a, 0x36, 0xab, 0xa5, 0x4b, 0x61, 0x57, 0xfb,
+	0x0a, 0xc6, 0xaf, 0x97, 0xfa, 0xcb, 0x46, 0xcf, 0x6b, 0xee, 0x29, 0xf4, 0xf1, 0x52, 0xc9, 0x32,
+	0xf6, 0x67, 0xc1, 0x5f, 0xa4, 0xbb, 0x44, 0xf6, 0x8d, 0x2a, 0xff, 0x8f, 0xbd, 0x0d, 0x20, 0x74,
+	0x84, 0x49, 0x8e, 0x19, 0x72, 0x6c, 0xe5, 0xbb, 0xbc, 0xa5, 0x9e, 0x49, 0x13, 0x7b, 0xd6, 0x0a,
+	0x4a, 0x94, 0x30, 0xab, 0x6c, 0x44, 0x2c, 0xf5, 0x5c, 0x7c, 0x58, 0x02, 0x03, 0x6d, 0xcd, 0xa0,
+	0x5d, 0x46, 0xc9, 0xc4, 0xea, 0x5b, 0x1e, 0xa5, 0x9e, 0xd8, 0xe9, 0xd8, 0x29, 0x44, 0xf3, 0xda,
+	0x0b, 0x5a, 0x73, 0x94, 0x3c, 0xb2, 0xd8, 0x5d, 0x97, 0x52, 0x4f, 0x34, 0xd5, 0x08, 0x5f, 0xd6,
+	0x66, 0xc4, 0x61, 0x0b, 0xbe, 0x6b, 0x13, 0xc2, 0x0d, 0x35, 0xbe, 0xcd, 0xe6, 0xb8, 0xd7, 0x7a,
+	0x5b, 0xe3, 0x17, 0x80, 0x6f, 0x33, 0x1a, 0xf6, 0x02, 0x00, 0x1d, 0x39, 0x2f, 0x30, 0xdd, 0x71,
+	0x9f, 0x88, 0x07, 0x0d, 0xdf, 0xea, 0xd4, 0xa7, 0x9e, 0x18, 0xae, 0x5d, 0x87, 0x1d, 0xb9, 0x30,
+	0x0d, 0x08, 0xb9, 0x6f, 0x91, 0x3a, 0x92, 0xa9, 0xe7, 0x12, 0x76, 0xe4, 0x12, 0x36, 0x6c, 0x49,
+	0xeb, 0x64, 0xa2, 0x94, 0x14, 0x67, 0x7d, 0x9b, 0xb2, 0xb3, 0xc7, 0x3f, 0xb7, 0x53, 0xff, 0x76,
+	0x3b, 0xf5, 0x7f, 0x6d, 0xa7, 0xfe, 0x8f, 0xdf, 0x53, 0xef, 0x33, 0x6d, 0xfb, 0x66, 0x75, 0x71,
+	0xd1, 0xa3, 0xbf, 0x93, 0x93, 0x3f, 0x03, 0x00, 0xe7, 0x97, 0x37, 0xbf, 0x5d, 0x04, 0x00, 0x00,
 }
@@ -39,6 +39,22 @@ func TestMarshal(t *testing.T) {
 			name:  "regexp query",
 			query: MustCreateRegexpQuery([]byte("fruit"), []byte(".*ple")),
 		},
+		{
+			name:  "prefix query",
+			query: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:  "term range query",
+			query: NewTermRangeQuery([]byte("fruit"), []byte("apple"), nil, true, false),
+		},
+		{
+			name:  "field query",
+			query: NewFieldQuery([]byte("fruit")),
+		},
+		{
+			name:  "terms query",
+			query: NewTermsQuery([]byte("fruit"), [][]byte{[]byte("banana"), []byte("apple")}),
+		},
 		{
 			name:  "negation query",
 			query: NewNegationQuery(NewTermQuery([]byte("fruit"), []byte("apple"))),
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idx
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index/multi"
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/executor"
+)
+
+var (
+	errSearcherClosed = errors.New("searcher is closed")
+)
+
+type reverseIndex struct {
+	index        multi.Index
+	executorOpts executor.Options
+}
+
+// NewIndex returns a new Index.
+func NewIndex(opts Options) (Index, error) {
+	idx, err := multi.NewIndex(newMultiOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &reverseIndex{
+		index:        idx,
+		executorOpts: opts.ExecutorOptions(),
+	}, nil
+}
+
+func (i *reverseIndex) Insert(d doc.Document) error {
+	_, err := i.index.Insert(d)
+	return err
+}
+
+func (i *reverseIndex) Delete(id []byte) error {
+	return i.index.Delete(id)
+}
+
+func (i *reverseIndex) DeleteByQuery(q Query) error {
+	return i.index.DeleteByQuery(q.SearchQuery())
+}
+
+func (i *reverseIndex) Searcher() (Searcher, error) {
+	rs, err := i.index.Readers()
+	if err != nil {
+		return nil, err
+	}
+	return &searcher{
+		executor: executor.NewExecutor(rs, i.executorOpts),
+	}, nil
+}
+
+func (i *reverseIndex) Close() error {
+	return i.index.Close()
+}
+
+type searcher struct {
+	sync.RWMutex
+
+	executor search.Executor
+	closed   bool
+}
+
+func (s *searcher) Search(q Query) (doc.Iterator, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return nil, errSearcherClosed
+	}
+	return s.executor.Execute(q.SearchQuery(), search.ExecuteOptions{})
+}
+
+func (s *searcher) Count(q Query) (int, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.closed {
+		return 0, errSearcherClosed
+	}
+	return s.executor.Count(q.SearchQuery())
+}
+
+func (s *searcher) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return errSearcherClosed
+	}
+	s.closed = true
+	s.Unlock()
+	return s.executor.Close()
+}
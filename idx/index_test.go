@@ -0,0 +1,246 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idx
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDocuments = []doc.Document{
+		doc.Document{
+			ID: []byte("apple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("apple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("red"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("banana"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("banana"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("pineapple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("pineapple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+	}
+)
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    Query
+		expected []doc.Document
+	}{
+		{
+			name:     "term query",
+			query:    NewTermQuery([]byte("color"), []byte("yellow")),
+			expected: []doc.Document{testDocuments[1], testDocuments[2]},
+		},
+		{
+			name:     "regexp query",
+			query:    MustCreateRegexpQuery([]byte("fruit"), []byte(".*apple")),
+			expected: []doc.Document{testDocuments[0], testDocuments[2]},
+		},
+		{
+			// Regular expressions must match the whole term so "pineapple" is not matched.
+			name:     "regexp query matches whole terms",
+			query:    MustCreateRegexpQuery([]byte("fruit"), []byte("appl.")),
+			expected: []doc.Document{testDocuments[0]},
+		},
+		{
+			name:     "prefix query",
+			query:    NewPrefixQuery([]byte("fruit"), []byte("pine")),
+			expected: []doc.Document{testDocuments[2]},
+		},
+		{
+			name:     "term range query",
+			query:    NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("pineapple"), false, true),
+			expected: []doc.Document{testDocuments[1], testDocuments[2]},
+		},
+		{
+			name:     "terms query",
+			query:    NewTermsQuery([]byte("fruit"), [][]byte{[]byte("pineapple"), []byte("apple"), []byte("kiwi")}),
+			expected: []doc.Document{testDocuments[0], testDocuments[2]},
+		},
+		{
+			name:     "field query",
+			query:    NewFieldQuery([]byte("color")),
+			expected: testDocuments,
+		},
+		{
+			name:     "negation query",
+			query:    NewNegationQuery(NewTermQuery([]byte("color"), []byte("yellow"))),
+			expected: []doc.Document{testDocuments[0]},
+		},
+		{
+			name: "conjunction query",
+			query: NewConjunctionQuery(
+				NewTermQuery([]byte("color"), []byte("yellow")),
+				MustCreateRegexpQuery([]byte("fruit"), []byte(".*apple")),
+			),
+			expected: []doc.Document{testDocuments[2]},
+		},
+		{
+			name: "disjunction query",
+			query: NewDisjunctionQuery(
+				NewTermQuery([]byte("fruit"), []byte("apple")),
+				NewTermQuery([]byte("fruit"), []byte("banana")),
+			),
+			expected: []doc.Document{testDocuments[0], testDocuments[1]},
+		},
+	}
+
+	// Use a small segment size so the documents are spread across several segments.
+	idx, err := NewIndex(NewOptions().SetMaxSegmentSize(2))
+	require.NoError(t, err)
+	for _, d := range testDocuments {
+		require.NoError(t, idx.Insert(d))
+	}
+
+	s, err := idx.Searcher()
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			iter, err := s.Search(test.query)
+			require.NoError(t, err)
+
+			var actual []doc.Document
+			for iter.Next() {
+				actual = append(actual, iter.Current())
+			}
+			require.NoError(t, iter.Err())
+			require.NoError(t, iter.Close())
+			require.Equal(t, test.expected, actual)
+
+			count, err := s.Count(test.query)
+			require.NoError(t, err)
+			require.Equal(t, len(test.expected), count)
+		})
+	}
+
+	require.NoError(t, s.Close())
+	_, err = s.Search(testDocumentsQuery())
+	require.Equal(t, errSearcherClosed, err)
+
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexSearcherIsPointInTime(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, idx.Insert(testDocuments[0]))
+
+	s, err := idx.Searcher()
+	require.NoError(t, err)
+
+	// Documents inserted after the searcher was created are not visible to it.
+	require.NoError(t, idx.Insert(testDocuments[1]))
+
+	iter, err := s.Search(testDocumentsQuery())
+	require.NoError(t, err)
+
+	require.True(t, iter.Next())
+	require.Equal(t, testDocuments[0], iter.Current())
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+
+	count, err := s.Count(testDocumentsQuery())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	require.NoError(t, s.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexDelete(t *testing.T) {
+	idx, err := NewIndex(NewOptions().SetMaxSegmentSize(2))
+	require.NoError(t, err)
+	for _, d := range testDocuments {
+		require.NoError(t, idx.Insert(d))
+	}
+
+	s, err := idx.Searcher()
+	require.NoError(t, err)
+
+	require.NoError(t, idx.Delete(testDocuments[0].ID))
+	require.NoError(t, idx.DeleteByQuery(NewTermQuery([]byte("fruit"), []byte("pineapple"))))
+
+	// Deletions are not visible to searchers created before them.
+	iter, err := s.Search(testDocumentsQuery())
+	require.NoError(t, err)
+	require.Equal(t, testDocuments, collectDocs(t, iter))
+	require.NoError(t, s.Close())
+
+	s, err = idx.Searcher()
+	require.NoError(t, err)
+	iter, err = s.Search(testDocumentsQuery())
+	require.NoError(t, err)
+	require.Equal(t, []doc.Document{testDocuments[1]}, collectDocs(t, iter))
+	require.NoError(t, s.Close())
+
+	require.NoError(t, idx.Close())
+}
+
+func collectDocs(t *testing.T, iter doc.Iterator) []doc.Document {
+	var docs []doc.Document
+	for iter.Next() {
+		docs = append(docs, iter.Current())
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	return docs
+}
+
+// testDocumentsQuery returns a query which matches all of the test documents.
+func testDocumentsQuery() Query {
+	return MustCreateRegexpQuery([]byte("fruit"), []byte(".*"))
+}
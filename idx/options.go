@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idx
+
+import (
+	"github.com/m3db/m3ninx/index/multi"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/search/executor"
+
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultMaxSegmentSize = 1 << 16
+)
+
+// Options is a collection of knobs for an Index.
+type Options interface {
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetMaxSegmentSize sets the maximum number of documents in the segment being
+	// inserted into before it is sealed and a new one is created.
+	SetMaxSegmentSize(value int64) Options
+
+	// MaxSegmentSize returns the maximum number of documents in the segment being
+	// inserted into before it is sealed and a new one is created.
+	MaxSegmentSize() int64
+
+	// SetExecutorOptions sets the options for the executors used to search the index.
+	SetExecutorOptions(value executor.Options) Options
+
+	// ExecutorOptions returns the options for the executors used to search the index.
+	ExecutorOptions() executor.Options
+}
+
+type opts struct {
+	iopts          instrument.Options
+	maxSegmentSize int64
+	executorOpts   executor.Options
+}
+
+// NewOptions returns new options.
+func NewOptions() Options {
+	return &opts{
+		iopts:          instrument.NewOptions(),
+		maxSegmentSize: defaultMaxSegmentSize,
+		executorOpts:   executor.NewOptions(),
+	}
+}
+
+func (o *opts) SetInstrumentOptions(v instrument.Options) Options {
+	opts := *o
+	opts.iopts = v
+	return &opts
+}
+
+func (o *opts) InstrumentOptions() instrument.Options {
+	return o.iopts
+}
+
+func (o *opts) SetMaxSegmentSize(v int64) Options {
+	opts := *o
+	opts.maxSegmentSize = v
+	return &opts
+}
+
+func (o *opts) MaxSegmentSize() int64 {
+	return o.maxSegmentSize
+}
+
+func (o *opts) SetExecutorOptions(v executor.Options) Options {
+	opts := *o
+	opts.executorOpts = v
+	return &opts
+}
+
+func (o *opts) ExecutorOptions() executor.Options {
+	return o.executorOpts
+}
+
+// newMultiOptions returns the options for the multi-segment index backing an Index.
+func newMultiOptions(opts Options) multi.Options {
+	iopts := opts.InstrumentOptions()
+	memOpts := mem.NewOptions().
+		SetInstrumentOptions(iopts)
+	return multi.NewOptions().
+		SetInstrumentOptions(iopts).
+		SetMemOptions(memOpts).
+		SetMaxActiveSegmentSize(opts.MaxSegmentSize())
+}
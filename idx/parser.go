@@ -0,0 +1,442 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idx
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/query"
+)
+
+// ParseError is an error encountered while parsing a query.
+type ParseError struct {
+	// Offset is the byte offset within the query at which the error was encountered.
+	Offset int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid query at offset %d: %s", e.Offset, e.Msg)
+}
+
+// ParseQuery parses a query from its text representation. The syntax is as follows:
+//
+//	field:term              matches documents which have the term for the field
+//	field:prefix*           matches documents which have a term beginning with the prefix
+//	field:/regexp/          matches documents which have a term matching the whole regexp
+//	field:[min TO max]      matches documents which have a term within the range, where
+//	                        "{" and "}" exclude a bound and "*" leaves a bound open
+//	field:(term, term)      matches documents which have any of the terms
+//	field:*                 matches documents which have the field
+//	NOT q, q AND q, q OR q  negation, conjunction and disjunction of queries
+//	(AND), (OR)             empty conjunction and disjunction, which match no documents
+//
+// NOT binds tighter than AND, which binds tighter than OR, and parentheses may be used
+// for grouping. Field names and terms which contain whitespace or punctuation, or which
+// are one of the keywords AND, OR, NOT or TO, must be written as double-quoted strings
+// using Go escape sequences. A "/" within a regexp must be escaped as "\/".
+func ParseQuery(s string) (Query, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return Query{}, err
+	}
+
+	p := parser{tokens: tokens}
+	q, err := p.parseDisjunction()
+	if err != nil {
+		return Query{}, err
+	}
+
+	if t := p.peek(); t.typ != tokenEOF {
+		return Query{}, p.errorf(t, "expected AND, OR or end of query, found %s", t)
+	}
+	return Query{query: q}, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &ParseError{Offset: t.start, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseDisjunction() (search.Query, error) {
+	q, err := p.parseConjunction()
+	if err != nil {
+		return nil, err
+	}
+
+	qs := []search.Query{q}
+	for p.peek().typ == tokenOr {
+		p.next()
+		q, err := p.parseConjunction()
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, q)
+	}
+
+	if len(qs) == 1 {
+		return qs[0], nil
+	}
+	return query.NewDisjunctionQuery(qs), nil
+}
+
+func (p *parser) parseConjunction() (search.Query, error) {
+	q, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	qs := []search.Query{q}
+	for p.peek().typ == tokenAnd {
+		p.next()
+		q, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, q)
+	}
+
+	if len(qs) == 1 {
+		return qs[0], nil
+	}
+	return query.NewConjunctionQuery(qs), nil
+}
+
+func (p *parser) parseUnary() (search.Query, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenNot:
+		q, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return query.NewNegationQuery(q), nil
+
+	case tokenLParen:
+		if q, ok := p.parseEmpty(); ok {
+			return q, nil
+		}
+
+		q, err := p.parseDisjunction()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.typ != tokenRParen {
+			return nil, p.errorf(t, "expected AND, OR or ')', found %s", t)
+		}
+		return q, nil
+
+	case tokenWord, tokenString:
+		return p.parseFieldQuery(t.value)
+	}
+
+	return nil, p.errorf(t, "expected field name, NOT or '(', found %s", t)
+}
+
+// parseEmpty parses an empty conjunction or disjunction, written as "(AND)" or "(OR)"
+// respectively, following an opening parenthesis. It returns false without consuming any
+// tokens if the parenthesis is not followed by one.
+func (p *parser) parseEmpty() (search.Query, bool) {
+	op := p.peek().typ
+	if op != tokenAnd && op != tokenOr {
+		return nil, false
+	}
+	if p.tokens[p.pos+1].typ != tokenRParen {
+		return nil, false
+	}
+	p.next()
+	p.next()
+
+	if op == tokenAnd {
+		return query.NewConjunctionQuery(nil), true
+	}
+	return query.NewDisjunctionQuery(nil), true
+}
+
+func (p *parser) parseFieldQuery(field []byte) (search.Query, error) {
+	if t := p.next(); t.typ != tokenColon {
+		return nil, p.errorf(t, "expected ':' after field name, found %s", t)
+	}
+
+	t := p.next()
+	switch t.typ {
+	case tokenStar:
+		return query.NewFieldQuery(field), nil
+
+	case tokenWord, tokenString:
+		// A "*" immediately following a term makes it a prefix.
+		if next := p.peek(); next.typ == tokenStar && next.start == t.end {
+			p.next()
+			return query.NewPrefixQuery(field, t.value), nil
+		}
+		return query.NewTermQuery(field, t.value), nil
+
+	case tokenRegexp:
+		q, err := query.NewRegexpQuery(field, t.value)
+		if err != nil {
+			return nil, p.errorf(t, "invalid regexp: %v", err)
+		}
+		return q, nil
+
+	case tokenLBracket, tokenLBrace:
+		return p.parseTermRange(field, t.typ == tokenLBracket)
+
+	case tokenLParen:
+		return p.parseTerms(field)
+	}
+
+	return nil, p.errorf(t, "expected term, prefix, regexp, range, terms or '*' after ':', found %s", t)
+}
+
+func (p *parser) parseTermRange(field []byte, minInclusive bool) (search.Query, error) {
+	min, err := p.parseBound()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.next(); t.typ != tokenTo {
+		return nil, p.errorf(t, "expected TO, found %s", t)
+	}
+
+	max, err := p.parseBound()
+	if err != nil {
+		return nil, err
+	}
+
+	var maxInclusive bool
+	switch t := p.next(); t.typ {
+	case tokenRBracket:
+		maxInclusive = true
+	case tokenRBrace:
+	default:
+		return nil, p.errorf(t, "expected ']' or '}', found %s", t)
+	}
+
+	return query.NewTermRangeQuery(field, min, max, minInclusive, maxInclusive), nil
+}
+
+func (p *parser) parseBound() ([]byte, error) {
+	switch t := p.next(); t.typ {
+	case tokenStar:
+		return nil, nil
+	case tokenWord, tokenString:
+		return t.value, nil
+	default:
+		return nil, p.errorf(t, "expected range bound or '*', found %s", t)
+	}
+}
+
+func (p *parser) parseTerms(field []byte) (search.Query, error) {
+	var terms [][]byte
+	if p.peek().typ == tokenRParen {
+		p.next()
+		return query.NewTermsQuery(field, terms), nil
+	}
+
+	for {
+		t := p.next()
+		if t.typ != tokenWord && t.typ != tokenString {
+			return nil, p.errorf(t, "expected term, found %s", t)
+		}
+		terms = append(terms, t.value)
+
+		switch t := p.next(); t.typ {
+		case tokenComma:
+		case tokenRParen:
+			return query.NewTermsQuery(field, terms), nil
+		default:
+			return nil, p.errorf(t, "expected ',' or ')', found %s", t)
+		}
+	}
+}
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenRegexp
+	tokenColon
+	tokenComma
+	tokenStar
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenLBrace
+	tokenRBrace
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTo
+)
+
+var (
+	punctuation = map[byte]tokenType{
+		':': tokenColon,
+		',': tokenComma,
+		'*': tokenStar,
+		'(': tokenLParen,
+		')': tokenRParen,
+		'[': tokenLBracket,
+		']': tokenRBracket,
+		'{': tokenLBrace,
+		'}': tokenRBrace,
+	}
+
+	keywords = map[string]tokenType{
+		"AND": tokenAnd,
+		"OR":  tokenOr,
+		"NOT": tokenNot,
+		"TO":  tokenTo,
+	}
+)
+
+// token is a lexical token of a query. The value of words, strings and regexps is
+// their unescaped contents.
+type token struct {
+	typ        tokenType
+	value      []byte
+	text       string
+	start, end int
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; ; {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+
+		if i == len(s) {
+			return append(tokens, token{typ: tokenEOF, start: i, end: i}), nil
+		}
+
+		t, err := scanToken(s, i)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		i = t.end
+	}
+}
+
+func scanToken(s string, start int) (token, error) {
+	c := s[start]
+	if typ, ok := punctuation[c]; ok {
+		return token{typ: typ, text: s[start : start+1], start: start, end: start + 1}, nil
+	}
+
+	switch c {
+	case '"':
+		return scanString(s, start)
+	case '/':
+		return scanRegexp(s, start)
+	case '\\':
+		return token{}, &ParseError{Offset: start, Msg: `unexpected character '\'`}
+	}
+
+	end := start
+	for end < len(s) && !isSpace(s[end]) && !isSpecial(s[end]) {
+		end++
+	}
+
+	text := s[start:end]
+	if typ, ok := keywords[text]; ok {
+		return token{typ: typ, text: text, start: start, end: end}, nil
+	}
+	return token{typ: tokenWord, value: []byte(text), text: text, start: start, end: end}, nil
+}
+
+func scanString(s string, start int) (token, error) {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			text := s[start : i+1]
+			value, err := strconv.Unquote(text)
+			if err != nil {
+				return token{}, &ParseError{Offset: start, Msg: fmt.Sprintf("invalid string %s", text)}
+			}
+			return token{typ: tokenString, value: []byte(value), text: text, start: start, end: i + 1}, nil
+		}
+	}
+	return token{}, &ParseError{Offset: start, Msg: "unterminated string"}
+}
+
+func scanRegexp(s string, start int) (token, error) {
+	var value []byte
+	for i := start + 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 == len(s) {
+				break
+			}
+			i++
+			if s[i] != '/' {
+				value = append(value, c)
+			}
+			value = append(value, s[i])
+		case '/':
+			return token{typ: tokenRegexp, value: value, text: s[start : i+1], start: start, end: i + 1}, nil
+		default:
+			value = append(value, c)
+		}
+	}
+	return token{}, &ParseError{Offset: start, Msg: "unterminated regexp"}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isSpecial returns whether c cannot appear within an unquoted word.
+func isSpecial(c byte) bool {
+	_, ok := punctuation[c]
+	return ok || c == '"' || c == '/' || c == '\\'
+}
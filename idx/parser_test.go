@@ -0,0 +1,275 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package idx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Query
+	}{
+		{
+			name:     "term",
+			input:    "fruit:apple",
+			expected: NewTermQuery([]byte("fruit"), []byte("apple")),
+		},
+		{
+			name:     "quoted term",
+			input:    `"fruit type":"granny smith\t\x00"`,
+			expected: NewTermQuery([]byte("fruit type"), []byte("granny smith\t\x00")),
+		},
+		{
+			name:     "prefix",
+			input:    "fruit:app*",
+			expected: NewPrefixQuery([]byte("fruit"), []byte("app")),
+		},
+		{
+			name:     "regexp",
+			input:    `path:/\/api\/v[0-9]+\.json/`,
+			expected: MustCreateRegexpQuery([]byte("path"), []byte(`/api/v[0-9]+\.json`)),
+		},
+		{
+			name:     "term range",
+			input:    "fruit:[apple TO banana}",
+			expected: NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+		},
+		{
+			name:     "open term range",
+			input:    "fruit:{* TO banana]",
+			expected: NewTermRangeQuery([]byte("fruit"), nil, []byte("banana"), false, true),
+		},
+		{
+			name:  "terms",
+			input: "fruit:(banana, apple)",
+			expected: NewTermsQuery([]byte("fruit"), [][]byte{
+				[]byte("apple"), []byte("banana"),
+			}),
+		},
+		{
+			name:     "field",
+			input:    "fruit:*",
+			expected: NewFieldQuery([]byte("fruit")),
+		},
+		{
+			name:  "precedence",
+			input: "service:api AND region:/us-.*/ AND NOT env:staging AND (a:b OR c:d)",
+			expected: NewConjunctionQuery(
+				NewTermQuery([]byte("service"), []byte("api")),
+				MustCreateRegexpQuery([]byte("region"), []byte("us-.*")),
+				NewNegationQuery(NewTermQuery([]byte("env"), []byte("staging"))),
+				NewDisjunctionQuery(
+					NewTermQuery([]byte("a"), []byte("b")),
+					NewTermQuery([]byte("c"), []byte("d")),
+				),
+			),
+		},
+		{
+			name:  "or binds less tightly than and",
+			input: "a:b AND NOT c:d OR e:f",
+			expected: NewDisjunctionQuery(
+				NewConjunctionQuery(
+					NewTermQuery([]byte("a"), []byte("b")),
+					NewNegationQuery(NewTermQuery([]byte("c"), []byte("d"))),
+				),
+				NewTermQuery([]byte("e"), []byte("f")),
+			),
+		},
+		{
+			name:  "negated group",
+			input: " NOT ( a:b OR c:d ) ",
+			expected: NewNegationQuery(NewDisjunctionQuery(
+				NewTermQuery([]byte("a"), []byte("b")),
+				NewTermQuery([]byte("c"), []byte("d")),
+			)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := ParseQuery(test.input)
+			require.NoError(t, err)
+			require.True(t, test.expected.Equal(q), "expected %v, got %v", test.expected, q)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int
+		msg    string
+	}{
+		{input: "", offset: 0, msg: "expected field name, NOT or '(', found end of query"},
+		{input: "fruit", offset: 5, msg: "expected ':' after field name, found end of query"},
+		{input: "fruit:apple banana", offset: 12, msg: `expected AND, OR or end of query, found "banana"`},
+		{input: "fruit:apple *", offset: 12, msg: `expected AND, OR or end of query, found "*"`},
+		{input: "(fruit:apple", offset: 12, msg: "expected AND, OR or ')', found end of query"},
+		{input: "fruit:apple AND OR", offset: 16, msg: `expected field name, NOT or '(', found "OR"`},
+		{input: "()", offset: 1, msg: `expected field name, NOT or '(', found ")"`},
+		{input: "(AND fruit:apple)", offset: 1, msg: `expected field name, NOT or '(', found "AND"`},
+		{input: "fruit:AND", offset: 6, msg: `expected term, prefix, regexp, range, terms or '*' after ':', found "AND"`},
+		{input: `fruit:"apple`, offset: 6, msg: "unterminated string"},
+		{input: `fruit:"\q"`, offset: 6, msg: `invalid string "\q"`},
+		{input: "fruit:/app", offset: 6, msg: "unterminated regexp"},
+		{input: "fruit:/(/", offset: 6, msg: "invalid regexp: error parsing regexp: missing closing ): `(`"},
+		{input: "fruit:[a b]", offset: 9, msg: `expected TO, found "b"`},
+		{input: "fruit:[a TO b)", offset: 13, msg: `expected ']' or '}', found ")"`},
+		{input: "fruit:[( TO b]", offset: 7, msg: `expected range bound or '*', found "("`},
+		{input: "fruit:(a b)", offset: 9, msg: `expected ',' or ')', found "b"`},
+		{input: "fruit:(a,)", offset: 9, msg: `expected term, found ")"`},
+		{input: `fruit:a\b`, offset: 7, msg: `unexpected character '\'`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := ParseQuery(test.input)
+			require.Error(t, err)
+
+			parseErr, ok := err.(*ParseError)
+			require.True(t, ok)
+			require.Equal(t, test.offset, parseErr.Offset)
+			require.Equal(t, test.msg, parseErr.Msg)
+		})
+	}
+}
+
+func TestQueryStringRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    Query
+		expected string
+	}{
+		{
+			name:     "term",
+			query:    NewTermQuery([]byte("fruit"), []byte("apple")),
+			expected: "fruit:apple",
+		},
+		{
+			name:     "quoted term",
+			query:    NewTermQuery([]byte("fruit:type"), []byte("AND")),
+			expected: `"fruit:type":"AND"`,
+		},
+		{
+			name:     "non-printable term",
+			query:    NewTermQuery([]byte("fruit"), []byte("\x00\xffé")),
+			expected: `fruit:"\x00\xffé"`,
+		},
+		{
+			name:     "empty term",
+			query:    NewTermQuery([]byte("fruit"), nil),
+			expected: `fruit:""`,
+		},
+		{
+			name:     "prefix",
+			query:    NewPrefixQuery([]byte("fruit"), []byte("app le")),
+			expected: `fruit:"app le"*`,
+		},
+		{
+			name:     "regexp",
+			query:    MustCreateRegexpQuery([]byte("path"), []byte(`/api\\/v[0-9]+\.json`)),
+			expected: `path:/\/api\\\/v[0-9]+\.json/`,
+		},
+		{
+			name:     "term range",
+			query:    NewTermRangeQuery([]byte("fruit"), nil, []byte("*"), true, false),
+			expected: `fruit:[* TO "*"}`,
+		},
+		{
+			name:     "terms",
+			query:    NewTermsQuery([]byte("fruit"), [][]byte{[]byte("b"), []byte("a c")}),
+			expected: `fruit:("a c", b)`,
+		},
+		{
+			name:     "no terms",
+			query:    NewTermsQuery([]byte("fruit"), nil),
+			expected: "fruit:()",
+		},
+		{
+			name:     "field",
+			query:    NewFieldQuery([]byte("fruit")),
+			expected: "fruit:*",
+		},
+		{
+			name: "conjunction",
+			query: NewConjunctionQuery(
+				NewNegationQuery(NewTermQuery([]byte("a"), []byte("b"))),
+				NewDisjunctionQuery(
+					NewTermQuery([]byte("c"), []byte("d")),
+					NewTermQuery([]byte("e"), []byte("f")),
+				),
+				NewNegationQuery(NewConjunctionQuery(
+					NewTermQuery([]byte("g"), []byte("h")),
+					NewTermQuery([]byte("i"), []byte("j")),
+				)),
+			),
+			expected: "(c:d OR e:f) AND NOT a:b AND NOT (g:h AND i:j)",
+		},
+		{
+			name: "disjunction",
+			query: NewDisjunctionQuery(
+				NewConjunctionQuery(
+					NewTermQuery([]byte("a"), []byte("b")),
+					NewNegationQuery(NewTermQuery([]byte("c"), []byte("d"))),
+				),
+				NewNegationQuery(NewNegationQuery(NewTermQuery([]byte("e"), []byte("f")))),
+				NewConjunctionQuery(NewTermQuery([]byte("g"), []byte("h"))),
+			),
+			expected: "a:b AND NOT c:d OR NOT NOT e:f OR g:h",
+		},
+		{
+			name:     "empty conjunction",
+			query:    NewConjunctionQuery(),
+			expected: "(AND)",
+		},
+		{
+			name:     "empty disjunction",
+			query:    NewDisjunctionQuery(),
+			expected: "(OR)",
+		},
+		{
+			name: "nested empty queries",
+			query: NewDisjunctionQuery(
+				NewNegationQuery(NewConjunctionQuery()),
+				NewConjunctionQuery(
+					NewTermQuery([]byte("a"), []byte("b")),
+					NewDisjunctionQuery(),
+				),
+			),
+			expected: "NOT (AND) OR a:b AND (OR)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.query.String())
+
+			q, err := ParseQuery(test.query.String())
+			require.NoError(t, err)
+			require.True(t, test.query.Equal(q), "expected %v, got %v", test.query, q)
+		})
+	}
+}
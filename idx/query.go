@@ -21,17 +21,31 @@
 package idx
 
 import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/m3db/m3ninx/generated/proto/querypb"
 	"github.com/m3db/m3ninx/search"
 	"github.com/m3db/m3ninx/search/query"
 )
 
+const (
+	disjunctionPrecedence = iota
+	conjunctionPrecedence
+	negationPrecedence
+)
+
 // Query encapsulates a search query for an index.
 type Query struct {
 	query search.Query
 }
 
+// String returns the text representation of the query which can be parsed by ParseQuery.
 func (q Query) String() string {
-	return q.query.String()
+	var buf bytes.Buffer
+	writeQuery(&buf, q.query.ToProto(), disjunctionPrecedence)
+	return buf.String()
 }
 
 // NewTermQuery returns a new query for finding documents which match a term exactly.
@@ -42,6 +56,8 @@ func NewTermQuery(field, term []byte) Query {
 }
 
 // NewRegexpQuery returns a new query for finding documents which match a regular expression.
+// The regular expression must match a term in its entirety, e.g. "app" does not match
+// "apple" but "app.*" does.
 func NewRegexpQuery(field, regexp []byte) (Query, error) {
 	q, err := query.NewRegexpQuery(field, regexp)
 	if err != nil {
@@ -63,6 +79,38 @@ func MustCreateRegexpQuery(field, regexp []byte) Query {
 	}
 }
 
+// NewPrefixQuery returns a new query for finding documents which have a term beginning with
+// a prefix.
+func NewPrefixQuery(field, prefix []byte) Query {
+	return Query{
+		query: query.NewPrefixQuery(field, prefix),
+	}
+}
+
+// NewTermRangeQuery returns a new query for finding documents which have a term within a
+// lexicographic range. An empty bound leaves that side of the range open.
+func NewTermRangeQuery(field, min, max []byte, minInclusive, maxInclusive bool) Query {
+	return Query{
+		query: query.NewTermRangeQuery(field, min, max, minInclusive, maxInclusive),
+	}
+}
+
+// NewFieldQuery returns a new query for finding documents which have a field, regardless of
+// its value.
+func NewFieldQuery(field []byte) Query {
+	return Query{
+		query: query.NewFieldQuery(field),
+	}
+}
+
+// NewTermsQuery returns a new query for finding documents which match any of the given
+// terms exactly.
+func NewTermsQuery(field []byte, terms [][]byte) Query {
+	return Query{
+		query: query.NewTermsQuery(field, terms),
+	}
+}
+
 // NewNegationQuery returns a new query for finding documents which don't match a given query.
 func NewNegationQuery(q Query) Query {
 	return Query{
@@ -103,3 +151,150 @@ func (q Query) SearchQuery() search.Query {
 func (q Query) Equal(o Query) bool {
 	return q.query.Equal(o.query)
 }
+
+// writeQuery writes the text representation of a query. Conjunctions and disjunctions
+// are parenthesized if they would otherwise bind less tightly than the given precedence.
+func writeQuery(buf *bytes.Buffer, q *querypb.Query, precedence int) {
+	switch q := q.Query.(type) {
+	case *querypb.Query_Term:
+		writeField(buf, q.Term.Field)
+		writeValue(buf, q.Term.Term)
+
+	case *querypb.Query_Regexp:
+		writeField(buf, q.Regexp.Field)
+		writeRegexp(buf, q.Regexp.Regexp)
+
+	case *querypb.Query_Prefix:
+		writeField(buf, q.Prefix.Field)
+		writeValue(buf, q.Prefix.Prefix)
+		buf.WriteByte('*')
+
+	case *querypb.Query_TermRange:
+		writeField(buf, q.TermRange.Field)
+		if q.TermRange.MinInclusive {
+			buf.WriteByte('[')
+		} else {
+			buf.WriteByte('{')
+		}
+		writeBound(buf, q.TermRange.Min)
+		buf.WriteString(" TO ")
+		writeBound(buf, q.TermRange.Max)
+		if q.TermRange.MaxInclusive {
+			buf.WriteByte(']')
+		} else {
+			buf.WriteByte('}')
+		}
+
+	case *querypb.Query_Field:
+		writeField(buf, q.Field.Field)
+		buf.WriteByte('*')
+
+	case *querypb.Query_Terms:
+		writeField(buf, q.Terms.Field)
+		buf.WriteByte('(')
+		for i, term := range q.Terms.Terms {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeValue(buf, term)
+		}
+		buf.WriteByte(')')
+
+	case *querypb.Query_Negation:
+		buf.WriteString("NOT ")
+		writeQuery(buf, q.Negation.Query, negationPrecedence)
+
+	case *querypb.Query_Conjunction:
+		writeQueries(buf, q.Conjunction.Queries, " AND ", conjunctionPrecedence, precedence)
+
+	case *querypb.Query_Disjunction:
+		writeQueries(buf, q.Disjunction.Queries, " OR ", disjunctionPrecedence, precedence)
+	}
+}
+
+func writeQueries(buf *bytes.Buffer, qs []*querypb.Query, op string, opPrecedence, precedence int) {
+	switch len(qs) {
+	case 0:
+		// Empty conjunctions and disjunctions are written as the operator alone so they
+		// can be told apart when parsed.
+		buf.WriteByte('(')
+		buf.WriteString(strings.TrimSpace(op))
+		buf.WriteByte(')')
+		return
+	case 1:
+		writeQuery(buf, qs[0], precedence)
+		return
+	}
+
+	parenthesize := precedence > opPrecedence
+	if parenthesize {
+		buf.WriteByte('(')
+	}
+	for i, q := range qs {
+		if i > 0 {
+			buf.WriteString(op)
+		}
+		writeQuery(buf, q, opPrecedence)
+	}
+	if parenthesize {
+		buf.WriteByte(')')
+	}
+}
+
+func writeField(buf *bytes.Buffer, field []byte) {
+	writeValue(buf, field)
+	buf.WriteByte(':')
+}
+
+func writeBound(buf *bytes.Buffer, bound []byte) {
+	if len(bound) == 0 {
+		buf.WriteByte('*')
+		return
+	}
+	writeValue(buf, bound)
+}
+
+// writeValue writes a field name or term as a word if possible and otherwise as a
+// quoted string.
+func writeValue(buf *bytes.Buffer, value []byte) {
+	if isWord(value) {
+		buf.Write(value)
+		return
+	}
+	buf.WriteString(strconv.Quote(string(value)))
+}
+
+func isWord(value []byte) bool {
+	if len(value) == 0 {
+		return false
+	}
+	if _, ok := keywords[string(value)]; ok {
+		return false
+	}
+	for _, c := range value {
+		if c <= ' ' || c >= 0x7f || isSpecial(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeRegexp writes a regexp between slashes, escaping any unescaped slashes within it.
+func writeRegexp(buf *bytes.Buffer, regexp []byte) {
+	buf.WriteByte('/')
+	for i := 0; i < len(regexp); i++ {
+		switch c := regexp[i]; c {
+		case '\\':
+			buf.WriteByte(c)
+			if i+1 < len(regexp) {
+				i++
+				buf.WriteByte(regexp[i])
+			}
+		case '/':
+			buf.WriteString(`\/`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('/')
+}
@@ -29,6 +29,12 @@ type Index interface {
 	// Insert inserts a document into the index.
 	Insert(d doc.Document) error
 
+	// Delete deletes the document with the given ID from the index.
+	Delete(id []byte) error
+
+	// DeleteByQuery deletes all documents matching the given query from the index.
+	DeleteByQuery(q Query) error
+
 	// Searcher returns a Searcher over a point-in-time view of the index.
 	Searcher() (Searcher, error)
 
@@ -40,5 +46,8 @@ type Index interface {
 type Searcher interface {
 	Search(q Query) (doc.Iterator, error)
 
+	// Count returns the number of documents matching the given query.
+	Count(q Query) (int, error)
+
 	Close() error
 }
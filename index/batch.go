@@ -42,6 +42,11 @@ type Batch struct {
 	// If true, on the other hand, then any errors encountered indexing a document will cause
 	// the entire batch to fail and none of the documents in the batch will be indexed.
 	AllowPartialUpdates bool
+
+	// If Upsert is true then a document in the batch with the same ID as a document already
+	// in the index replaces the existing document. If false, on the other hand, then the
+	// document in the batch is ignored and the existing document is left unchanged.
+	Upsert bool
 }
 
 // BatchOption is an option for a Batch.
@@ -65,6 +70,16 @@ func AllowPartialUpdates() BatchOption {
 	})
 }
 
+// Upsert permits an index to replace existing documents with documents in a batch which
+// have the same ID. Readers created before the batch is inserted continue to see the
+// existing documents.
+func Upsert() BatchOption {
+	return batchOptionFunc(func(b Batch) Batch {
+		b.Upsert = true
+		return b
+	})
+}
+
 // NewBatch returns a Batch of documents.
 func NewBatch(docs []doc.Document, opts ...BatchOption) Batch {
 	b := Batch{Docs: docs}
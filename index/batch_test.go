@@ -59,6 +59,31 @@ func TestBatchAllowPartialUpdates(t *testing.T) {
 	}
 }
 
+func TestBatchUpsert(t *testing.T) {
+	tests := []struct {
+		name     string
+		batch    Batch
+		expected bool
+	}{
+		{
+			name:     "off",
+			batch:    NewBatch(nil),
+			expected: false,
+		},
+		{
+			name:     "on",
+			batch:    NewBatch(nil, Upsert()),
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.batch.Upsert)
+		})
+	}
+}
+
 func TestBatchPartialError(t *testing.T) {
 	var (
 		idxs = []int{3, 7, 13}
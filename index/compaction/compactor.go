@@ -0,0 +1,160 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"bytes"
+	"sync"
+
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/index/segment/fs"
+)
+
+type compactor struct {
+	sync.Mutex
+
+	opts        Options
+	writer      fs.Writer
+	mergeWriter fs.MergeWriter
+}
+
+// NewCompactor returns a new Compactor.
+func NewCompactor(opts Options) Compactor {
+	return &compactor{
+		opts:        opts,
+		writer:      fs.NewWriterWithOpts(opts.WriterOptions()),
+		mergeWriter: fs.NewMergeWriter(opts.WriterOptions()),
+	}
+}
+
+func (c *compactor) Compact(segs []sgmt.Segment) (fs.Segment, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	// fs segments are merged directly, any other segments are first written out as fs
+	// segments so they can be merged alongside them.
+	var (
+		fsSegs    = make([]fs.Segment, 0, len(segs))
+		converted []fs.Segment
+	)
+	defer func() {
+		for _, s := range converted {
+			s.Close()
+		}
+	}()
+
+	for _, s := range segs {
+		if fsSeg, ok := s.(fs.Segment); ok {
+			fsSegs = append(fsSegs, fsSeg)
+			continue
+		}
+
+		// Segments without any documents which have not been deleted cannot be written.
+		empty, err := isEmpty(s)
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			continue
+		}
+
+		fsSeg, err := c.convert(s)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, fsSeg)
+		fsSegs = append(fsSegs, fsSeg)
+	}
+
+	if err := c.mergeWriter.Reset(fsSegs); err != nil {
+		return nil, err
+	}
+	defer c.mergeWriter.Reset(nil)
+
+	return c.writeSegment(c.mergeWriter)
+}
+
+// convert writes out the provided segment as an fs segment.
+func (c *compactor) convert(s sgmt.Segment) (fs.Segment, error) {
+	if err := c.writer.Reset(s); err != nil {
+		return nil, err
+	}
+	defer c.writer.Reset(nil)
+
+	return c.writeSegment(c.writer)
+}
+
+// isEmpty returns whether all of the documents in the provided segment have been deleted.
+func isEmpty(s sgmt.Segment) (bool, error) {
+	reader, err := s.Reader()
+	if err != nil {
+		return false, err
+	}
+	live, err := reader.MatchAll()
+	if err != nil {
+		reader.Close()
+		return false, err
+	}
+	return live.IsEmpty(), reader.Close()
+}
+
+// writeSegment writes out the files of the provided writer as an fs segment backed by
+// memory.
+func (c *compactor) writeSegment(w fs.FilesWriter) (fs.Segment, error) {
+	var (
+		docsDataBuffer  bytes.Buffer
+		docsIndexBuffer bytes.Buffer
+		postingsBuffer  bytes.Buffer
+		fstTermsBuffer  bytes.Buffer
+		fstFieldsBuffer bytes.Buffer
+	)
+
+	if err := w.WriteDocumentsData(&docsDataBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteDocumentsIndex(&docsIndexBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WritePostingsOffsets(&postingsBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteFSTTerms(&fstTermsBuffer); err != nil {
+		return nil, err
+	}
+	if err := w.WriteFSTFields(&fstFieldsBuffer); err != nil {
+		return nil, err
+	}
+
+	data := fs.SegmentData{
+		MajorVersion:  w.MajorVersion(),
+		MinorVersion:  w.MinorVersion(),
+		Metadata:      w.Metadata(),
+		DocsData:      docsDataBuffer.Bytes(),
+		DocsIdxData:   docsIndexBuffer.Bytes(),
+		PostingsData:  postingsBuffer.Bytes(),
+		FSTTermsData:  fstTermsBuffer.Bytes(),
+		FSTFieldsData: fstFieldsBuffer.Bytes(),
+	}
+	return fs.NewSegment(data, fs.NewSegmentOpts{
+		PostingsListPool:  c.opts.PostingsListPool(),
+		PostingsListCache: c.opts.PostingsListCache(),
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/postings"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDocuments = []doc.Document{
+		doc.Document{
+			ID: []byte("apple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("apple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("red"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("banana"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("banana"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("pineapple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("pineapple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+	}
+)
+
+func TestCompactorCompact(t *testing.T) {
+	c := NewCompactor(NewOptions())
+
+	first := newTestSegment(t, 0, testDocuments[:2])
+	second := newTestSegment(t, 2, testDocuments[2:])
+
+	// Deleted documents are dropped from the compacted segment.
+	require.NoError(t, second.Delete(testDocuments[2].ID))
+
+	compacted, err := c.Compact([]sgmt.Segment{first, second})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), compacted.Size())
+
+	// Compacting a segment does not modify it.
+	require.Equal(t, int64(2), first.Size())
+
+	// The compacted segment can itself be compacted.
+	recompacted, err := c.Compact([]sgmt.Segment{compacted, first})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), recompacted.Size())
+
+	r, err := recompacted.Reader()
+	require.NoError(t, err)
+	iter, err := r.AllDocs()
+	require.NoError(t, err)
+	var docs []doc.Document
+	for iter.Next() {
+		docs = append(docs, iter.Current())
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	require.Equal(t, testDocuments[:2], docs)
+	require.NoError(t, r.Close())
+
+	for _, s := range []sgmt.Segment{first, second, compacted, recompacted} {
+		require.NoError(t, s.Close())
+	}
+}
+
+func newTestSegment(t *testing.T, offset postings.ID, docs []doc.Document) sgmt.MutableSegment {
+	s, err := mem.NewSegment(offset, mem.NewOptions())
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := s.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err = s.Seal()
+	require.NoError(t, err)
+	return s
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"github.com/m3db/m3ninx/index/segment/fs"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+)
+
+// Options is a collection of knobs for a Compactor.
+type Options interface {
+	// SetPostingsListPool sets the postings list pool used by the compacted segments.
+	SetPostingsListPool(value postings.Pool) Options
+
+	// PostingsListPool returns the postings list pool used by the compacted segments.
+	PostingsListPool() postings.Pool
+
+	// SetWriterOptions sets the options used to write the compacted segments.
+	SetWriterOptions(value fs.WriterOpts) Options
+
+	// WriterOptions returns the options used to write the compacted segments.
+	WriterOptions() fs.WriterOpts
+
+	// SetPostingsListCache sets the postings list cache shared by the compacted segments.
+	// A nil cache disables caching.
+	SetPostingsListCache(value *fs.PostingsListCache) Options
+
+	// PostingsListCache returns the postings list cache shared by the compacted segments.
+	PostingsListCache() *fs.PostingsListCache
+}
+
+type opts struct {
+	postingsPool      postings.Pool
+	writerOpts        fs.WriterOpts
+	postingsListCache *fs.PostingsListCache
+}
+
+// NewOptions returns new options.
+func NewOptions() Options {
+	return &opts{
+		postingsPool: postings.NewPool(nil, roaring.NewPostingsList),
+		writerOpts: fs.WriterOpts{
+			FieldPostingsLists: true,
+			DocFrequencies:     true,
+		},
+	}
+}
+
+func (o *opts) SetPostingsListPool(v postings.Pool) Options {
+	opts := *o
+	opts.postingsPool = v
+	return &opts
+}
+
+func (o *opts) PostingsListPool() postings.Pool {
+	return o.postingsPool
+}
+
+func (o *opts) SetWriterOptions(v fs.WriterOpts) Options {
+	opts := *o
+	opts.writerOpts = v
+	return &opts
+}
+
+func (o *opts) WriterOptions() fs.WriterOpts {
+	return o.writerOpts
+}
+
+func (o *opts) SetPostingsListCache(v *fs.PostingsListCache) Options {
+	opts := *o
+	opts.postingsListCache = v
+	return &opts
+}
+
+func (o *opts) PostingsListCache() *fs.PostingsListCache {
+	return o.postingsListCache
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"sort"
+)
+
+const (
+	defaultMinTierSize     = 1 << 16
+	defaultTierSizeRatio   = 8
+	defaultSegmentsPerTier = 4
+	defaultMaxSegmentSize  = 1 << 24
+)
+
+// TieredPolicyOptions is a collection of knobs for a tiered compaction policy.
+type TieredPolicyOptions struct {
+	// MinTierSize is the maximum number of documents in a segment in the lowest tier.
+	MinTierSize int64
+
+	// TierSizeRatio is the ratio between the maximum number of documents in a segment
+	// in one tier and the maximum number of documents in a segment in the tier below.
+	TierSizeRatio int64
+
+	// SegmentsPerTier is the number of segments which must be in a tier before the
+	// segments in that tier are compacted.
+	SegmentsPerTier int
+
+	// MaxSegmentSize is the number of documents above which a segment is no longer
+	// considered for compaction.
+	MaxSegmentSize int64
+}
+
+// NewTieredPolicyOptions returns new TieredPolicyOptions with default values.
+func NewTieredPolicyOptions() TieredPolicyOptions {
+	return TieredPolicyOptions{
+		MinTierSize:     defaultMinTierSize,
+		TierSizeRatio:   defaultTierSizeRatio,
+		SegmentsPerTier: defaultSegmentsPerTier,
+		MaxSegmentSize:  defaultMaxSegmentSize,
+	}
+}
+
+type tieredPolicy struct {
+	opts TieredPolicyOptions
+}
+
+// NewTieredPolicy returns a Policy which groups segments into tiers of exponentially
+// increasing size and compacts all of the segments in a tier together once the tier
+// contains enough segments. Compacting segments of a similar size together keeps the
+// number of times each document is rewritten logarithmic in the size of the index.
+func NewTieredPolicy(opts TieredPolicyOptions) Policy {
+	return &tieredPolicy{
+		opts: opts,
+	}
+}
+
+func (p *tieredPolicy) Plan(sizes []int64) [][]int {
+	tiers := make(map[int][]int)
+	for i, size := range sizes {
+		if size > p.opts.MaxSegmentSize {
+			continue
+		}
+		tier := p.tier(size)
+		tiers[tier] = append(tiers[tier], i)
+	}
+
+	var plan [][]int
+	for _, idxs := range tiers {
+		if len(idxs) < p.opts.SegmentsPerTier || len(idxs) < 2 {
+			continue
+		}
+		plan = append(plan, idxs)
+	}
+
+	// Compact the segments in the lowest tiers first.
+	sort.Slice(plan, func(i, j int) bool {
+		return p.tier(sizes[plan[i][0]]) < p.tier(sizes[plan[j][0]])
+	})
+	return plan
+}
+
+// tier returns the tier of a segment with the given number of documents.
+func (p *tieredPolicy) tier(size int64) int {
+	var (
+		tier    = 0
+		maxSize = p.opts.MinTierSize
+	)
+	for size > maxSize && p.opts.TierSizeRatio > 1 {
+		tier++
+		maxSize *= p.opts.TierSizeRatio
+	}
+	return tier
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package compaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieredPolicyPlan(t *testing.T) {
+	opts := TieredPolicyOptions{
+		MinTierSize:     10,
+		TierSizeRatio:   10,
+		SegmentsPerTier: 3,
+		MaxSegmentSize:  1000,
+	}
+
+	tests := []struct {
+		name     string
+		sizes    []int64
+		expected [][]int
+	}{
+		{
+			name:     "no segments",
+			sizes:    nil,
+			expected: nil,
+		},
+		{
+			name:     "too few segments in a tier",
+			sizes:    []int64{5, 10, 50, 60},
+			expected: nil,
+		},
+		{
+			name:     "single full tier",
+			sizes:    []int64{5, 10, 50, 1},
+			expected: [][]int{{0, 1, 3}},
+		},
+		{
+			name:     "multiple full tiers",
+			sizes:    []int64{50, 5, 60, 10, 70, 1},
+			expected: [][]int{{1, 3, 5}, {0, 2, 4}},
+		},
+		{
+			name:     "segments above the max size are not compacted",
+			sizes:    []int64{2000, 1500, 3000},
+			expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewTieredPolicy(opts)
+			require.Equal(t, test.expected, p.Plan(test.sizes))
+		})
+	}
+}
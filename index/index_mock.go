@@ -19,7 +19,7 @@
 // THE SOFTWARE.
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/m3db/m3ninx/index (interfaces: Reader,DocRetriever)
+// Source: github.com/m3db/m3ninx/index (interfaces: Reader,DocRetriever,IDDocIterator)
 
 // Package index is a generated GoMock package.
 package index
@@ -95,10 +95,23 @@ func (mr *MockReaderMockRecorder) Doc(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doc", reflect.TypeOf((*MockReader)(nil).Doc), arg0)
 }
 
+// DocFrequency mocks base method
+func (m *MockReader) DocFrequency(arg0, arg1 []byte) (int, error) {
+	ret := m.ctrl.Call(m, "DocFrequency", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DocFrequency indicates an expected call of DocFrequency
+func (mr *MockReaderMockRecorder) DocFrequency(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DocFrequency", reflect.TypeOf((*MockReader)(nil).DocFrequency), arg0, arg1)
+}
+
 // Docs mocks base method
-func (m *MockReader) Docs(arg0 postings.List) (doc.Iterator, error) {
+func (m *MockReader) Docs(arg0 postings.List) (IDDocIterator, error) {
 	ret := m.ctrl.Call(m, "Docs", arg0)
-	ret0, _ := ret[0].(doc.Iterator)
+	ret0, _ := ret[0].(IDDocIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -108,6 +121,32 @@ func (mr *MockReaderMockRecorder) Docs(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Docs", reflect.TypeOf((*MockReader)(nil).Docs), arg0)
 }
 
+// FieldNames mocks base method
+func (m *MockReader) FieldNames() ([][]byte, error) {
+	ret := m.ctrl.Call(m, "FieldNames")
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FieldNames indicates an expected call of FieldNames
+func (mr *MockReaderMockRecorder) FieldNames() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FieldNames", reflect.TypeOf((*MockReader)(nil).FieldNames))
+}
+
+// FieldTerms mocks base method
+func (m *MockReader) FieldTerms(arg0 []byte) (TermsIterator, error) {
+	ret := m.ctrl.Call(m, "FieldTerms", arg0)
+	ret0, _ := ret[0].(TermsIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FieldTerms indicates an expected call of FieldTerms
+func (mr *MockReaderMockRecorder) FieldTerms(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FieldTerms", reflect.TypeOf((*MockReader)(nil).FieldTerms), arg0)
+}
+
 // MatchAll mocks base method
 func (m *MockReader) MatchAll() (postings.MutableList, error) {
 	ret := m.ctrl.Call(m, "MatchAll")
@@ -121,6 +160,32 @@ func (mr *MockReaderMockRecorder) MatchAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchAll", reflect.TypeOf((*MockReader)(nil).MatchAll))
 }
 
+// MatchField mocks base method
+func (m *MockReader) MatchField(arg0 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchField", arg0)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchField indicates an expected call of MatchField
+func (mr *MockReaderMockRecorder) MatchField(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockReader)(nil).MatchField), arg0)
+}
+
+// MatchPrefix mocks base method
+func (m *MockReader) MatchPrefix(arg0, arg1 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchPrefix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix
+func (mr *MockReaderMockRecorder) MatchPrefix(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockReader)(nil).MatchPrefix), arg0, arg1)
+}
+
 // MatchRegexp mocks base method
 func (m *MockReader) MatchRegexp(arg0, arg1 []byte, arg2 *regexp.Regexp) (postings.List, error) {
 	ret := m.ctrl.Call(m, "MatchRegexp", arg0, arg1, arg2)
@@ -147,6 +212,32 @@ func (mr *MockReaderMockRecorder) MatchTerm(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerm", reflect.TypeOf((*MockReader)(nil).MatchTerm), arg0, arg1)
 }
 
+// MatchTermRange mocks base method
+func (m *MockReader) MatchTermRange(arg0, arg1, arg2 []byte, arg3, arg4 bool) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchTermRange", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTermRange indicates an expected call of MatchTermRange
+func (mr *MockReaderMockRecorder) MatchTermRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTermRange", reflect.TypeOf((*MockReader)(nil).MatchTermRange), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MatchTerms mocks base method
+func (m *MockReader) MatchTerms(arg0 []byte, arg1 [][]byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTerms indicates an expected call of MatchTerms
+func (mr *MockReaderMockRecorder) MatchTerms(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerms", reflect.TypeOf((*MockReader)(nil).MatchTerms), arg0, arg1)
+}
+
 // MockDocRetriever is a mock of DocRetriever interface
 type MockDocRetriever struct {
 	ctrl     *gomock.Controller
@@ -182,3 +273,86 @@ func (m *MockDocRetriever) Doc(arg0 postings.ID) (doc.Document, error) {
 func (mr *MockDocRetrieverMockRecorder) Doc(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doc", reflect.TypeOf((*MockDocRetriever)(nil).Doc), arg0)
 }
+
+// MockIDDocIterator is a mock of IDDocIterator interface
+type MockIDDocIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDDocIteratorMockRecorder
+}
+
+// MockIDDocIteratorMockRecorder is the mock recorder for MockIDDocIterator
+type MockIDDocIteratorMockRecorder struct {
+	mock *MockIDDocIterator
+}
+
+// NewMockIDDocIterator creates a new mock instance
+func NewMockIDDocIterator(ctrl *gomock.Controller) *MockIDDocIterator {
+	mock := &MockIDDocIterator{ctrl: ctrl}
+	mock.recorder = &MockIDDocIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIDDocIterator) EXPECT() *MockIDDocIteratorMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockIDDocIterator) Close() error {
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockIDDocIteratorMockRecorder) Close() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIDDocIterator)(nil).Close))
+}
+
+// Current mocks base method
+func (m *MockIDDocIterator) Current() doc.Document {
+	ret := m.ctrl.Call(m, "Current")
+	ret0, _ := ret[0].(doc.Document)
+	return ret0
+}
+
+// Current indicates an expected call of Current
+func (mr *MockIDDocIteratorMockRecorder) Current() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockIDDocIterator)(nil).Current))
+}
+
+// Err mocks base method
+func (m *MockIDDocIterator) Err() error {
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err
+func (mr *MockIDDocIteratorMockRecorder) Err() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockIDDocIterator)(nil).Err))
+}
+
+// Next mocks base method
+func (m *MockIDDocIterator) Next() bool {
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next
+func (mr *MockIDDocIteratorMockRecorder) Next() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockIDDocIterator)(nil).Next))
+}
+
+// PostingsID mocks base method
+func (m *MockIDDocIterator) PostingsID() postings.ID {
+	ret := m.ctrl.Call(m, "PostingsID")
+	ret0, _ := ret[0].(postings.ID)
+	return ret0
+}
+
+// PostingsID indicates an expected call of PostingsID
+func (mr *MockIDDocIteratorMockRecorder) PostingsID() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostingsID", reflect.TypeOf((*MockIDDocIterator)(nil).PostingsID))
+}
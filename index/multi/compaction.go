@@ -0,0 +1,264 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"github.com/m3db/m3ninx/index"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/postings"
+
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+// compactionTask is a group of sealed segments being compacted into a single segment.
+type compactionTask struct {
+	sources []*refCountedSegment
+
+	// Readers over each of the sources, created before the sources are compacted. They
+	// keep the sources open during compaction and are used to identify documents which
+	// are deleted from the sources while they are being compacted.
+	readers index.Readers
+}
+
+func (t compactionTask) segments() []sgmt.Segment {
+	segs := make([]sgmt.Segment, 0, len(t.sources))
+	for _, s := range t.sources {
+		segs = append(segs, s.segment)
+	}
+	return segs
+}
+
+// compactionLoop compacts the sealed segments each time it is triggered until the
+// index is closed.
+func (i *multiIndex) compactionLoop() {
+	defer i.compaction.wg.Done()
+
+	logger := i.opts.InstrumentOptions().Logger()
+	for {
+		select {
+		case <-i.compaction.doneCh:
+			return
+		case <-i.compaction.triggerCh:
+			if err := i.Compact(); err != nil && err != errIndexClosed {
+				logger.Errorf("unable to compact segments: %v", err)
+			}
+		}
+	}
+}
+
+// triggerCompaction signals the background compaction loop to run if it is not already
+// waiting to do so.
+func (i *multiIndex) triggerCompaction() {
+	if i.compaction.triggerCh == nil {
+		return
+	}
+	select {
+	case i.compaction.triggerCh <- struct{}{}:
+	default:
+	}
+}
+
+func (i *multiIndex) Compact() error {
+	if i.opts.CompactionPolicy() == nil {
+		return nil
+	}
+
+	// Only a single compaction runs at a time so the sources of a task cannot be
+	// compacted by another task.
+	i.compaction.Lock()
+	defer i.compaction.Unlock()
+
+	tasks, err := i.planCompaction()
+	if err != nil {
+		return err
+	}
+
+	var multiErr xerrors.MultiError
+	for j, task := range tasks {
+		if err := i.compact(task); err != nil {
+			multiErr = multiErr.Add(err)
+			// Release the readers of the tasks which will no longer be run.
+			for _, remaining := range tasks[j+1:] {
+				multiErr = multiErr.Add(remaining.readers.Close())
+			}
+			break
+		}
+	}
+	return multiErr.FinalError()
+}
+
+// planCompaction selects the groups of sealed segments to compact.
+func (i *multiIndex) planCompaction() ([]compactionTask, error) {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if i.state.closed {
+		return nil, errIndexClosed
+	}
+
+	sizes := make([]int64, 0, len(i.state.sealed))
+	for _, s := range i.state.sealed {
+		sizes = append(sizes, s.segment.Size())
+	}
+
+	plan := i.opts.CompactionPolicy().Plan(sizes)
+	tasks := make([]compactionTask, 0, len(plan))
+	for _, idxs := range plan {
+		task := compactionTask{
+			sources: make([]*refCountedSegment, 0, len(idxs)),
+			readers: make(index.Readers, 0, len(idxs)),
+		}
+		for _, idx := range idxs {
+			s := i.state.sealed[idx]
+			r, err := s.reader()
+			if err != nil {
+				task.readers.Close()
+				for _, t := range tasks {
+					t.readers.Close()
+				}
+				return nil, err
+			}
+			task.sources = append(task.sources, s)
+			task.readers = append(task.readers, r)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// compact compacts the sources of the task into a new segment and swaps it into the
+// index in place of the sources.
+func (i *multiIndex) compact(task compactionTask) error {
+	// Snapshot the documents in each source before compacting so that any deletions
+	// made while the sources are being compacted can be applied to the new segment.
+	live := make([]postings.List, 0, len(task.readers))
+	for _, r := range task.readers {
+		pl, err := r.MatchAll()
+		if err != nil {
+			task.readers.Close()
+			return err
+		}
+		live = append(live, pl)
+	}
+
+	compacted, err := i.compactor.Compact(task.segments())
+	if err != nil {
+		task.readers.Close()
+		return err
+	}
+
+	err = i.swap(task, live, compacted)
+	if closeErr := task.readers.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// swap replaces the sources of the task with the compacted segment.
+func (i *multiIndex) swap(task compactionTask, live []postings.List, compacted sgmt.Segment) error {
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed {
+		compacted.Close()
+		return errIndexClosed
+	}
+
+	for j, s := range task.sources {
+		if err := applyDeletes(compacted, s.segment, task.readers[j], live[j]); err != nil {
+			compacted.Close()
+			return err
+		}
+	}
+
+	var (
+		isSource = make(map[*refCountedSegment]struct{}, len(task.sources))
+		sealed   = make([]*refCountedSegment, 0, len(i.state.sealed)-len(task.sources)+1)
+		swapped  = false
+	)
+	for _, s := range task.sources {
+		isSource[s] = struct{}{}
+	}
+	for _, s := range i.state.sealed {
+		if _, ok := isSource[s]; !ok {
+			sealed = append(sealed, s)
+			continue
+		}
+		// The compacted segment takes the place of the first of its sources.
+		if !swapped {
+			sealed = append(sealed, newRefCountedSegment(compacted))
+			swapped = true
+		}
+	}
+	i.state.sealed = sealed
+
+	// Release the index's references to the sources, they are closed once the Readers
+	// which reference them are closed.
+	var multiErr xerrors.MultiError
+	for _, s := range task.sources {
+		multiErr = multiErr.Add(s.decRef())
+	}
+	return multiErr.FinalError()
+}
+
+// applyDeletes deletes the documents from the compacted segment which were deleted
+// from the source segment after the live snapshot was taken. The reader must be the
+// reader from which the snapshot was taken.
+func applyDeletes(
+	compacted sgmt.Segment,
+	src sgmt.Segment,
+	snapshot index.Reader,
+	live postings.List,
+) error {
+	r, err := src.Reader()
+	if err != nil {
+		return err
+	}
+	current, err := r.MatchAll()
+	if err != nil {
+		r.Close()
+		return err
+	}
+	if err := r.Close(); err != nil {
+		return err
+	}
+
+	deleted := live.Clone()
+	if err := deleted.Difference(current); err != nil {
+		return err
+	}
+
+	iter := deleted.Iterator()
+	for iter.Next() {
+		d, err := snapshot.Doc(iter.Current())
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if err := deleteID(compacted, d.ID); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return err
+	}
+	return iter.Close()
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/compaction"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/index/segment/fs"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCompactionOptions() Options {
+	policy := compaction.NewTieredPolicy(compaction.TieredPolicyOptions{
+		MinTierSize:     4,
+		TierSizeRatio:   4,
+		SegmentsPerTier: 2,
+		MaxSegmentSize:  64,
+	})
+	return NewOptions().
+		SetMaxActiveSegmentSize(1).
+		SetCompactionPolicy(policy)
+}
+
+func TestIndexCompact(t *testing.T) {
+	idx, err := NewIndex(newTestCompactionOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+
+	// Readers created before compaction continue to work once the segments they
+	// reference have been compacted.
+	before, err := idx.Readers()
+	require.NoError(t, err)
+
+	require.NoError(t, idx.Compact())
+	require.Equal(t, 2, idx.NumSegments())
+
+	after, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, after, 2)
+	_, ok := after[0].(*reader).segment.segment.(fs.Segment)
+	require.True(t, ok)
+
+	for _, rs := range []index.Readers{before, after} {
+		docs := matchTerm(t, rs, []byte("color"), []byte("yellow"))
+		require.Equal(t, []doc.Document{testDocuments[1], testDocuments[2]}, docs)
+	}
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexCompactAppliesConcurrentDeletes(t *testing.T) {
+	idx, err := NewIndex(newTestCompactionOptions().SetCompactionPolicy(nil))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+
+	// Delete a document from the sources while they are being compacted.
+	mi := idx.(*multiIndex)
+	mi.opts = mi.opts.SetCompactionPolicy(newTestCompactionOptions().CompactionPolicy())
+	mi.compactor = &deletingCompactor{
+		Compactor: mi.compactor,
+		delete: func() {
+			require.NoError(t, idx.Delete(testDocuments[1].ID))
+		},
+	}
+
+	require.NoError(t, idx.Compact())
+	require.Equal(t, 2, idx.NumSegments())
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	docs := matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[2]}, docs)
+	require.NoError(t, rs.Close())
+
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexCompactionDisabledByDefault(t *testing.T) {
+	idx, err := NewIndex(NewOptions().SetMaxActiveSegmentSize(1))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+
+	// Without a compaction policy the sealed segments should be left as they are.
+	require.Nil(t, idx.(*multiIndex).compaction.triggerCh)
+	require.NoError(t, idx.Compact())
+	require.Equal(t, len(testDocuments)+1, idx.NumSegments())
+
+	require.NoError(t, idx.Close())
+}
+
+type deletingCompactor struct {
+	compaction.Compactor
+
+	delete func()
+}
+
+func (c *deletingCompactor) Compact(segs []sgmt.Segment) (fs.Segment, error) {
+	compacted, err := c.Compactor.Compact(segs)
+	c.delete()
+	return compacted, err
+}
@@ -0,0 +1,507 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/compaction"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search"
+
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+var (
+	errIndexClosed = errors.New("index is closed")
+)
+
+type multiIndex struct {
+	opts Options
+
+	state struct {
+		sync.RWMutex
+		closed bool
+
+		// The active segment is the only segment documents are inserted into. It is
+		// tracked both as a mutable segment and as a reference counted segment.
+		active       sgmt.MutableSegment
+		activeRef    *refCountedSegment
+		activeOffset postings.ID
+
+		// Immutable segments, in the order they were added to the index.
+		sealed []*refCountedSegment
+	}
+
+	compactor  compaction.Compactor
+	compaction struct {
+		sync.Mutex
+
+		triggerCh chan struct{}
+		doneCh    chan struct{}
+		wg        sync.WaitGroup
+	}
+}
+
+// NewIndex returns a new multi-segment index.
+func NewIndex(opts Options) (Index, error) {
+	i := &multiIndex{
+		opts:      opts,
+		compactor: compaction.NewCompactor(opts.CompactionOptions()),
+	}
+
+	active, err := mem.NewSegment(0, opts.MemOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	i.state.active = active
+	i.state.activeRef = newRefCountedSegment(active)
+
+	if opts.CompactionPolicy() != nil {
+		i.compaction.triggerCh = make(chan struct{}, 1)
+		i.compaction.doneCh = make(chan struct{})
+		i.compaction.wg.Add(1)
+		go i.compactionLoop()
+	}
+	return i, nil
+}
+
+func (i *multiIndex) Insert(d doc.Document) ([]byte, error) {
+	i.state.RLock()
+	if i.state.closed {
+		i.state.RUnlock()
+		return nil, errIndexClosed
+	}
+
+	if d.HasID() {
+		contains, err := i.sealedContainsIDWithRLock(d.ID)
+		if err != nil {
+			i.state.RUnlock()
+			return nil, err
+		}
+
+		// The index already contains this document so there is nothing to insert.
+		if contains {
+			i.state.RUnlock()
+			return d.ID, nil
+		}
+	}
+
+	active := i.state.active
+	id, err := active.Insert(d)
+	i.state.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.maybeRotate(active); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+func (i *multiIndex) InsertBatch(b index.Batch) error {
+	if b.Upsert {
+		return i.upsertBatch(b)
+	}
+
+	i.state.RLock()
+	if i.state.closed {
+		i.state.RUnlock()
+		return errIndexClosed
+	}
+
+	batch, idxs, err := i.filterBatchWithRLock(b)
+	if err != nil {
+		i.state.RUnlock()
+		return err
+	}
+
+	active := i.state.active
+	err = active.InsertBatch(batch)
+	i.state.RUnlock()
+
+	if idxs != nil {
+		// Propagate any changes the segment made to the documents, such as generating
+		// IDs for them, back to the original batch.
+		for j, idx := range idxs {
+			b.Docs[idx] = batch.Docs[j]
+		}
+		err = remapBatchError(err, idxs)
+	}
+
+	if err != nil && !index.IsBatchPartialError(err) {
+		return err
+	}
+
+	if rotateErr := i.maybeRotate(active); rotateErr != nil {
+		return rotateErr
+	}
+	return err
+}
+
+// upsertBatch inserts the batch into the active segment and then deletes any documents
+// with the same IDs from the sealed segments. The state lock is held exclusively so
+// that Readers see either the existing documents or the documents replacing them.
+func (i *multiIndex) upsertBatch(b index.Batch) error {
+	i.state.Lock()
+	if i.state.closed {
+		i.state.Unlock()
+		return errIndexClosed
+	}
+
+	active := i.state.active
+	err := active.InsertBatch(b)
+	if err != nil && !index.IsBatchPartialError(err) {
+		i.state.Unlock()
+		return err
+	}
+
+	for _, d := range b.Docs {
+		// Documents which could not be inserted are cleared by the active segment.
+		if !d.HasID() {
+			continue
+		}
+		if deleteErr := i.deleteFromSealedWithLock(d.ID); deleteErr != nil {
+			i.state.Unlock()
+			return deleteErr
+		}
+	}
+	i.state.Unlock()
+
+	if rotateErr := i.maybeRotate(active); rotateErr != nil {
+		return rotateErr
+	}
+	return err
+}
+
+// filterBatchWithRLock removes the documents in the batch which are already contained
+// in one of the sealed segments. If any documents were removed it returns a new batch
+// along with the index of each remaining document in the original batch, otherwise it
+// returns the original batch and a nil slice of indexes. It must be called with the
+// state read lock.
+func (i *multiIndex) filterBatchWithRLock(b index.Batch) (index.Batch, []int, error) {
+	if len(i.state.sealed) == 0 {
+		return b, nil, nil
+	}
+
+	var (
+		docs = make([]doc.Document, 0, len(b.Docs))
+		idxs = make([]int, 0, len(b.Docs))
+	)
+	for j, d := range b.Docs {
+		if d.HasID() {
+			contains, err := i.sealedContainsIDWithRLock(d.ID)
+			if err != nil {
+				return index.Batch{}, nil, err
+			}
+			if contains {
+				continue
+			}
+		}
+		docs = append(docs, d)
+		idxs = append(idxs, j)
+	}
+
+	if len(docs) == len(b.Docs) {
+		return b, nil, nil
+	}
+
+	filtered := b
+	filtered.Docs = docs
+	return filtered, idxs, nil
+}
+
+// remapBatchError maps the indexes in a BatchPartialError for a filtered batch back
+// to the indexes of the documents in the original batch.
+func remapBatchError(err error, idxs []int) error {
+	partialErr, ok := err.(*index.BatchPartialError)
+	if !ok {
+		return err
+	}
+
+	remapped := index.NewBatchPartialError()
+	for _, e := range partialErr.Errs() {
+		remapped.Add(index.BatchError{Err: e.Err, Idx: idxs[e.Idx]})
+	}
+	return remapped
+}
+
+// sealedContainsIDWithRLock returns a bool indicating whether any of the sealed segments
+// contain the given ID. It must be called with the state read lock.
+func (i *multiIndex) sealedContainsIDWithRLock(id []byte) (bool, error) {
+	for _, s := range i.state.sealed {
+		// Segments without any documents may not be able to answer ContainsID, e.g. an
+		// fs segment without an ID field.
+		if s.segment.Size() == 0 {
+			continue
+		}
+		contains, err := s.segment.ContainsID(id)
+		if err != nil {
+			return false, err
+		}
+		if contains {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// maybeRotate rotates the active segment if it is still the provided segment and it
+// has reached the maximum size.
+func (i *multiIndex) maybeRotate(active sgmt.MutableSegment) error {
+	maxSize := i.opts.MaxActiveSegmentSize()
+	if maxSize <= 0 || active.Size() < maxSize {
+		return nil
+	}
+
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed || i.state.active != active {
+		// The index has been closed or the segment has already been rotated.
+		return nil
+	}
+
+	return i.rotateWithLock()
+}
+
+func (i *multiIndex) Rotate() error {
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed {
+		return errIndexClosed
+	}
+
+	return i.rotateWithLock()
+}
+
+// rotateWithLock seals the active segment, moves it into the list of sealed segments,
+// and replaces it with a new mutable segment. It must be called with the state lock.
+func (i *multiIndex) rotateWithLock() error {
+	size := i.state.active.Size()
+	if size == 0 {
+		// Nothing to gain from sealing an empty segment.
+		return nil
+	}
+
+	// Start assigning postings IDs where the current active segment left off so that
+	// postings IDs are unique across the segments created by the index.
+	nextOffset := i.state.activeOffset + postings.ID(size)
+	next, err := mem.NewSegment(nextOffset, i.opts.MemOptions())
+	if err != nil {
+		return err
+	}
+
+	// NB: the new segment is created before sealing the active segment so that the active
+	// segment remains writable if creating it fails.
+	if _, err := i.state.active.Seal(); err != nil {
+		next.Close()
+		return err
+	}
+
+	i.state.sealed = append(i.state.sealed, i.state.activeRef)
+	i.state.active = next
+	i.state.activeRef = newRefCountedSegment(next)
+	i.state.activeOffset = nextOffset
+
+	i.triggerCompaction()
+	return nil
+}
+
+func (i *multiIndex) Delete(id []byte) error {
+	// NB: the state lock is held exclusively so that no documents with the ID can be
+	// inserted while the segments are being updated.
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed {
+		return errIndexClosed
+	}
+
+	if err := i.deleteFromSealedWithLock(id); err != nil {
+		return err
+	}
+	return i.state.active.Delete(id)
+}
+
+// deleteFromSealedWithLock deletes the documents with the given ID from the sealed
+// segments. It must be called with the state lock.
+func (i *multiIndex) deleteFromSealedWithLock(id []byte) error {
+	for _, s := range i.state.sealed {
+		if s.segment.Size() == 0 {
+			continue
+		}
+		if err := deleteID(s.segment, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteID deletes the documents with the given ID from an immutable segment.
+func deleteID(s sgmt.Segment, id []byte) error {
+	r, err := s.Reader()
+	if err != nil {
+		return err
+	}
+
+	pl, err := r.MatchTerm(doc.IDReservedFieldName, id)
+	if err != nil {
+		r.Close()
+		return err
+	}
+
+	if !pl.IsEmpty() {
+		if err := s.DeletePostings(pl); err != nil {
+			r.Close()
+			return err
+		}
+	}
+	return r.Close()
+}
+
+func (i *multiIndex) DeleteByQuery(q search.Query) error {
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed {
+		return errIndexClosed
+	}
+
+	segments := make([]*refCountedSegment, 0, len(i.state.sealed)+1)
+	segments = append(segments, i.state.sealed...)
+	segments = append(segments, i.state.activeRef)
+
+	readers := make(index.Readers, 0, len(segments))
+	for _, s := range segments {
+		r, err := s.reader()
+		if err != nil {
+			readers.Close()
+			return err
+		}
+		readers = append(readers, r)
+	}
+
+	err := deleteMatches(q, segments, readers)
+	if closeErr := readers.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// deleteMatches deletes the documents matching the query from each of the segments,
+// the readers must correspond to the segments.
+func deleteMatches(q search.Query, segments []*refCountedSegment, readers index.Readers) error {
+	searcher, err := q.Searcher(readers)
+	if err != nil {
+		return err
+	}
+
+	for j := 0; searcher.Next(); j++ {
+		pl := searcher.Current()
+		if pl.IsEmpty() {
+			continue
+		}
+		if err := segments[j].segment.DeletePostings(pl); err != nil {
+			return err
+		}
+	}
+	return searcher.Err()
+}
+
+func (i *multiIndex) AddSegment(s sgmt.Segment) error {
+	i.state.Lock()
+	defer i.state.Unlock()
+	if i.state.closed {
+		return errIndexClosed
+	}
+
+	i.state.sealed = append(i.state.sealed, newRefCountedSegment(s))
+
+	i.triggerCompaction()
+	return nil
+}
+
+func (i *multiIndex) NumSegments() int {
+	i.state.RLock()
+	n := len(i.state.sealed) + 1
+	i.state.RUnlock()
+	return n
+}
+
+func (i *multiIndex) Readers() (index.Readers, error) {
+	i.state.RLock()
+	defer i.state.RUnlock()
+	if i.state.closed {
+		return nil, errIndexClosed
+	}
+
+	readers := make(index.Readers, 0, len(i.state.sealed)+1)
+	for _, s := range i.state.sealed {
+		r, err := s.reader()
+		if err != nil {
+			readers.Close()
+			return nil, err
+		}
+		readers = append(readers, r)
+	}
+
+	r, err := i.state.activeRef.reader()
+	if err != nil {
+		readers.Close()
+		return nil, err
+	}
+	readers = append(readers, r)
+
+	return readers, nil
+}
+
+func (i *multiIndex) Close() error {
+	i.state.Lock()
+	if i.state.closed {
+		i.state.Unlock()
+		return errIndexClosed
+	}
+	i.state.closed = true
+
+	var multiErr xerrors.MultiError
+	for _, s := range i.state.sealed {
+		multiErr = multiErr.Add(s.decRef())
+	}
+	multiErr = multiErr.Add(i.state.activeRef.decRef())
+
+	i.state.sealed = nil
+	i.state.active = nil
+	i.state.activeRef = nil
+	i.state.Unlock()
+
+	// NB: the state lock must be released before waiting for the compaction loop to
+	// exit since a running compaction acquires it to swap in the compacted segment.
+	if i.compaction.doneCh != nil {
+		close(i.compaction.doneCh)
+		i.compaction.wg.Wait()
+	}
+	return multiErr.FinalError()
+}
@@ -0,0 +1,399 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search/query"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDocuments = []doc.Document{
+		doc.Document{
+			ID: []byte("apple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("apple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("red"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("banana"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("banana"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+		doc.Document{
+			ID: []byte("pineapple"),
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("fruit"),
+					Value: []byte("pineapple"),
+				},
+				doc.Field{
+					Name:  []byte("color"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+	}
+)
+
+func TestIndexInsert(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, idx.NumSegments())
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	docs := matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[1], testDocuments[2]}, docs)
+
+	require.NoError(t, rs.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexInsertExistingIDInActiveSegment(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+
+	_, err = idx.Insert(testDocuments[0])
+	require.NoError(t, err)
+
+	// Inserting a document which already exists in the active segment is a no-op.
+	existing := doc.Document{
+		ID: testDocuments[0].ID,
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("color"),
+				Value: []byte("green"),
+			},
+		},
+	}
+	id, err := idx.Insert(existing)
+	require.NoError(t, err)
+	require.Equal(t, testDocuments[0].ID, id)
+	require.Equal(t, 1, idx.NumSegments())
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+
+	docs := matchTerm(t, rs, doc.IDReservedFieldName, testDocuments[0].ID)
+	require.Equal(t, []doc.Document{testDocuments[0]}, docs)
+
+	docs = matchTerm(t, rs, []byte("color"), []byte("green"))
+	require.Empty(t, docs)
+
+	require.NoError(t, rs.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexRotate(t *testing.T) {
+	idx, err := NewIndex(NewOptions().SetMaxActiveSegmentSize(2))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, idx.NumSegments())
+
+	// Inserting a document which already exists in a sealed segment is a no-op.
+	id, err := idx.Insert(testDocuments[0])
+	require.NoError(t, err)
+	require.Equal(t, testDocuments[0].ID, id)
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	docs := matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[1], testDocuments[2]}, docs)
+
+	docs = matchTerm(t, rs, []byte("fruit"), []byte("apple"))
+	require.Equal(t, []doc.Document{testDocuments[0]}, docs)
+
+	// Postings IDs are unique across the segments created by the index.
+	pl, err := rs[1].MatchTerm([]byte("fruit"), []byte("pineapple"))
+	require.NoError(t, err)
+	require.True(t, pl.Contains(postings.ID(2)))
+
+	require.NoError(t, rs.Close())
+
+	// Rotating an empty active segment does not create a new segment.
+	require.NoError(t, idx.Rotate())
+	require.NoError(t, idx.Rotate())
+	require.Equal(t, 3, idx.NumSegments())
+
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexInsertBatch(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+
+	_, err = idx.Insert(testDocuments[0])
+	require.NoError(t, err)
+	require.NoError(t, idx.Rotate())
+
+	invalid := doc.Document{
+		ID: []byte("invalid"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  doc.IDReservedFieldName,
+				Value: []byte("invalid"),
+			},
+		},
+	}
+	b := index.NewBatch([]doc.Document{
+		testDocuments[0],
+		invalid,
+		testDocuments[1],
+		testDocuments[2],
+	}, index.AllowPartialUpdates())
+
+	err = idx.InsertBatch(b)
+	require.Error(t, err)
+	require.True(t, index.IsBatchPartialError(err))
+
+	// The index of the failed document refers to its position in the original batch.
+	errs := err.(*index.BatchPartialError).Errs()
+	require.Len(t, errs, 1)
+	require.Equal(t, 1, errs[0].Idx)
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	docs := matchTerm(t, rs, doc.IDReservedFieldName, testDocuments[0].ID)
+	require.Equal(t, []doc.Document{testDocuments[0]}, docs)
+
+	docs = matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[1], testDocuments[2]}, docs)
+
+	require.NoError(t, rs.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexInsertBatchUpsert(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+	require.NoError(t, idx.Rotate())
+
+	before, err := idx.Readers()
+	require.NoError(t, err)
+
+	updated := doc.Document{
+		ID: testDocuments[0].ID,
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("apple"),
+			},
+			doc.Field{
+				Name:  []byte("color"),
+				Value: []byte("green"),
+			},
+		},
+	}
+	b := index.NewBatch([]doc.Document{updated}, index.Upsert())
+	require.NoError(t, idx.InsertBatch(b))
+
+	after, err := idx.Readers()
+	require.NoError(t, err)
+
+	// Readers created prior to the update still see the old version of the document.
+	docs := matchTerm(t, before, doc.IDReservedFieldName, updated.ID)
+	require.Equal(t, []doc.Document{testDocuments[0]}, docs)
+
+	docs = matchTerm(t, after, doc.IDReservedFieldName, updated.ID)
+	require.Equal(t, []doc.Document{updated}, docs)
+
+	docs = matchTerm(t, after, []byte("color"), []byte("red"))
+	require.Empty(t, docs)
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexDelete(t *testing.T) {
+	idx, err := NewIndex(NewOptions().SetMaxActiveSegmentSize(2))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, idx.NumSegments())
+
+	before, err := idx.Readers()
+	require.NoError(t, err)
+
+	// Delete a document from the sealed segment and one from the active segment.
+	require.NoError(t, idx.Delete(testDocuments[1].ID))
+	require.NoError(t, idx.Delete(testDocuments[2].ID))
+	require.NoError(t, idx.Delete([]byte("unknown")))
+
+	after, err := idx.Readers()
+	require.NoError(t, err)
+
+	// Readers created prior to the deletion still see the deleted documents.
+	docs := matchTerm(t, before, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[1], testDocuments[2]}, docs)
+
+	docs = matchTerm(t, after, []byte("color"), []byte("yellow"))
+	require.Empty(t, docs)
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+
+	// A deleted document can be inserted again.
+	_, err = idx.Insert(testDocuments[1])
+	require.NoError(t, err)
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	docs = matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Equal(t, []doc.Document{testDocuments[1]}, docs)
+	require.NoError(t, rs.Close())
+
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexDeleteByQuery(t *testing.T) {
+	idx, err := NewIndex(NewOptions().SetMaxActiveSegmentSize(2))
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := idx.Insert(d)
+		require.NoError(t, err)
+	}
+
+	q := query.NewTermQuery([]byte("color"), []byte("yellow"))
+	require.NoError(t, idx.DeleteByQuery(q))
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+
+	docs := matchTerm(t, rs, []byte("color"), []byte("yellow"))
+	require.Empty(t, docs)
+
+	docs = matchTerm(t, rs, []byte("color"), []byte("red"))
+	require.Equal(t, []doc.Document{testDocuments[0]}, docs)
+
+	require.NoError(t, rs.Close())
+	require.NoError(t, idx.Close())
+}
+
+func TestIndexReadersRetainSegments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		seg    = sgmt.NewMockSegment(mockCtrl)
+		reader = index.NewMockReader(mockCtrl)
+	)
+	seg.EXPECT().Reader().Return(reader, nil)
+
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+	require.NoError(t, idx.AddSegment(seg))
+	require.Equal(t, 2, idx.NumSegments())
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	// The segment must not be closed while a reader still references it.
+	require.NoError(t, idx.Close())
+
+	gomock.InOrder(
+		reader.EXPECT().Close().Return(nil),
+		seg.EXPECT().Close().Return(nil),
+	)
+	require.NoError(t, rs.Close())
+
+	_, err = idx.Readers()
+	require.Equal(t, errIndexClosed, err)
+}
+
+func TestIndexReaderDoubleClose(t *testing.T) {
+	idx, err := NewIndex(NewOptions())
+	require.NoError(t, err)
+
+	rs, err := idx.Readers()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	require.NoError(t, rs[0].Close())
+	require.Equal(t, errReaderClosed, rs[0].Close())
+	require.NoError(t, idx.Close())
+}
+
+func matchTerm(t *testing.T, rs index.Readers, field, term []byte) []doc.Document {
+	var docs []doc.Document
+	for _, r := range rs {
+		pl, err := r.MatchTerm(field, term)
+		require.NoError(t, err)
+
+		iter, err := r.Docs(pl)
+		require.NoError(t, err)
+		for iter.Next() {
+			docs = append(docs, iter.Current())
+		}
+		require.NoError(t, iter.Err())
+		require.NoError(t, iter.Close())
+	}
+	return docs
+}
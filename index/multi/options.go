@@ -0,0 +1,136 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"github.com/m3db/m3ninx/index/compaction"
+	"github.com/m3db/m3ninx/index/segment/mem"
+
+	"github.com/m3db/m3x/instrument"
+)
+
+const (
+	defaultMaxActiveSegmentSize = 1 << 16
+)
+
+// Options is a collection of knobs for a multi-segment index.
+type Options interface {
+	// SetInstrumentOptions sets the instrument options.
+	SetInstrumentOptions(value instrument.Options) Options
+
+	// InstrumentOptions returns the instrument options.
+	InstrumentOptions() instrument.Options
+
+	// SetMemOptions sets the options used to construct the in-memory segments.
+	SetMemOptions(value mem.Options) Options
+
+	// MemOptions returns the options used to construct the in-memory segments.
+	MemOptions() mem.Options
+
+	// SetMaxActiveSegmentSize sets the number of documents after which the active
+	// segment is sealed and a new one is created.
+	SetMaxActiveSegmentSize(value int64) Options
+
+	// MaxActiveSegmentSize returns the number of documents after which the active
+	// segment is sealed and a new one is created.
+	MaxActiveSegmentSize() int64
+
+	// SetCompactionPolicy sets the policy used to select the sealed segments to compact.
+	// Compaction is disabled if the policy is nil, the default.
+	SetCompactionPolicy(value compaction.Policy) Options
+
+	// CompactionPolicy returns the policy used to select the sealed segments to compact.
+	CompactionPolicy() compaction.Policy
+
+	// SetCompactionOptions sets the options used to compact segments.
+	SetCompactionOptions(value compaction.Options) Options
+
+	// CompactionOptions returns the options used to compact segments.
+	CompactionOptions() compaction.Options
+}
+
+type opts struct {
+	iopts                instrument.Options
+	memOpts              mem.Options
+	maxActiveSegmentSize int64
+	compactionPolicy     compaction.Policy
+	compactionOpts       compaction.Options
+}
+
+// NewOptions returns new options.
+func NewOptions() Options {
+	return &opts{
+		iopts:                instrument.NewOptions(),
+		memOpts:              mem.NewOptions(),
+		maxActiveSegmentSize: defaultMaxActiveSegmentSize,
+		compactionOpts:       compaction.NewOptions(),
+	}
+}
+
+func (o *opts) SetInstrumentOptions(v instrument.Options) Options {
+	opts := *o
+	opts.iopts = v
+	return &opts
+}
+
+func (o *opts) InstrumentOptions() instrument.Options {
+	return o.iopts
+}
+
+func (o *opts) SetMemOptions(v mem.Options) Options {
+	opts := *o
+	opts.memOpts = v
+	return &opts
+}
+
+func (o *opts) MemOptions() mem.Options {
+	return o.memOpts
+}
+
+func (o *opts) SetMaxActiveSegmentSize(v int64) Options {
+	opts := *o
+	opts.maxActiveSegmentSize = v
+	return &opts
+}
+
+func (o *opts) MaxActiveSegmentSize() int64 {
+	return o.maxActiveSegmentSize
+}
+
+func (o *opts) SetCompactionPolicy(v compaction.Policy) Options {
+	opts := *o
+	opts.compactionPolicy = v
+	return &opts
+}
+
+func (o *opts) CompactionPolicy() compaction.Policy {
+	return o.compactionPolicy
+}
+
+func (o *opts) SetCompactionOptions(v compaction.Options) Options {
+	opts := *o
+	opts.compactionOpts = v
+	return &opts
+}
+
+func (o *opts) CompactionOptions() compaction.Options {
+	return o.compactionOpts
+}
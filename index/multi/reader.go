@@ -0,0 +1,66 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3ninx/index"
+
+	xerrors "github.com/m3db/m3x/errors"
+)
+
+var (
+	errReaderClosed = errors.New("reader is closed")
+)
+
+// reader is an index.Reader which releases its reference to the underlying segment
+// when it's closed.
+type reader struct {
+	index.Reader
+
+	sync.Mutex
+	segment *refCountedSegment
+	closed  bool
+}
+
+func newReader(r index.Reader, s *refCountedSegment) index.Reader {
+	return &reader{
+		Reader:  r,
+		segment: s,
+	}
+}
+
+func (r *reader) Close() error {
+	r.Lock()
+	if r.closed {
+		r.Unlock()
+		return errReaderClosed
+	}
+	r.closed = true
+	r.Unlock()
+
+	var multiErr xerrors.MultiError
+	multiErr = multiErr.Add(r.Reader.Close())
+	multiErr = multiErr.Add(r.segment.decRef())
+	return multiErr.FinalError()
+}
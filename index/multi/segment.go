@@ -0,0 +1,97 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/m3db/m3ninx/index"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+)
+
+var (
+	errSegmentRefCountNegative = errors.New("segment reference count is negative")
+)
+
+// refCountedSegment wraps a segment with a reference count so that it is only closed
+// once the index and all of the Readers created from it are finished with it. The
+// owner of the segment holds the initial reference.
+type refCountedSegment struct {
+	sync.Mutex
+
+	segment sgmt.Segment
+	refs    int
+	closed  bool
+}
+
+func newRefCountedSegment(s sgmt.Segment) *refCountedSegment {
+	return &refCountedSegment{
+		segment: s,
+		refs:    1,
+	}
+}
+
+// incRef increments the reference count of the segment. It returns false if the
+// segment has already been closed.
+func (s *refCountedSegment) incRef() bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.closed {
+		return false
+	}
+	s.refs++
+	return true
+}
+
+// decRef decrements the reference count of the segment and closes it if there are
+// no references remaining.
+func (s *refCountedSegment) decRef() error {
+	s.Lock()
+	s.refs--
+	if s.refs > 0 {
+		s.Unlock()
+		return nil
+	}
+	if s.refs < 0 {
+		s.Unlock()
+		return errSegmentRefCountNegative
+	}
+	s.closed = true
+	s.Unlock()
+	return s.segment.Close()
+}
+
+// reader returns a Reader for the segment which holds a reference to the segment
+// until it's closed.
+func (s *refCountedSegment) reader() (index.Reader, error) {
+	if !s.incRef() {
+		return nil, sgmt.ErrClosed
+	}
+
+	r, err := s.segment.Reader()
+	if err != nil {
+		// NB: we can't have dropped the last reference since we're still holding one.
+		s.decRef()
+		return nil, err
+	}
+	return newReader(r, s), nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package multi
+
+import (
+	"github.com/m3db/m3ninx/index"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/search"
+)
+
+// Index is an index.Index composed of a single active mutable segment, into which
+// all documents are inserted, and a list of immutable segments. Once the active
+// segment reaches its configured size it is sealed and a new active segment is
+// created in its place.
+type Index interface {
+	index.Index
+
+	// AddSegment adds an immutable segment to the index. The index assumes ownership
+	// of the segment and will close it once it's no longer referenced by the index or
+	// by any Readers.
+	AddSegment(s sgmt.Segment) error
+
+	// DeleteByQuery deletes all documents matching the given query. Readers created
+	// prior to the deletion continue to see the deleted documents.
+	DeleteByQuery(q search.Query) error
+
+	// Rotate seals the active segment and replaces it with a new, empty, one.
+	Rotate() error
+
+	// Compact compacts the sealed segments selected by the compaction policy into new
+	// fs segments. If a compaction policy is configured, compaction is also run in the
+	// background whenever the active segment is sealed. Segments which are compacted are
+	// closed once they are no longer referenced by any Readers.
+	Compact() error
+
+	// NumSegments returns the number of segments in the index, including the active
+	// segment.
+	NumSegments() int
+}
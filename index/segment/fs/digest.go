@@ -0,0 +1,215 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"sync"
+
+	"github.com/m3db/m3ninx/index/segment/fs/encoding"
+)
+
+const digestLen = 4
+
+var (
+	errDigestMissing = errors.New("file is too short to contain a digest")
+
+	digestTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// segmentDigests are the digests of each of the files of a segment.
+type segmentDigests struct {
+	docsData      uint32
+	docsIdxData   uint32
+	postingsData  uint32
+	fstTermsData  uint32
+	fstFieldsData uint32
+}
+
+// writerMinorVersion returns the MinorVersion of the segments written with the provided
+// options.
+func writerMinorVersion(opts WriterOpts) int {
+	if opts.FileDigests {
+		return fileDigestsMinorVersion
+	}
+	return fileDigestsMinorVersion - 1
+}
+
+// writeFile writes out a file using the provided function, followed by a trailer
+// containing the CRC32 digest of the file's contents if withDigest is set.
+func writeFile(
+	iow io.Writer,
+	enc *encoding.Encoder,
+	withDigest bool,
+	write func(io.Writer) error,
+) error {
+	if !withDigest {
+		return write(iow)
+	}
+	return writeWithDigest(iow, enc, write)
+}
+
+// writeWithDigest writes out a file using the provided function followed by a trailer
+// containing the CRC32 digest of the file's contents.
+func writeWithDigest(
+	iow io.Writer,
+	enc *encoding.Encoder,
+	write func(io.Writer) error,
+) error {
+	dw := &digestWriter{
+		w:      iow,
+		digest: crc32.New(digestTable),
+	}
+	if err := write(dw); err != nil {
+		return err
+	}
+
+	enc.Reset()
+	enc.PutUint32(dw.digest.Sum32())
+	_, err := iow.Write(enc.Bytes())
+	return err
+}
+
+type digestWriter struct {
+	w      io.Writer
+	digest hash.Hash32
+}
+
+func (w *digestWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.digest.Write(p[:n])
+	return n, err
+}
+
+// stripDigests removes the digest trailer from each of the files of the provided segment
+// data and returns the digests.
+func stripDigests(data *SegmentData) (segmentDigests, error) {
+	var (
+		digests segmentDigests
+		err     error
+	)
+	for _, f := range segmentFiles(data, &digests) {
+		*f.data, *f.digest, err = splitDigest(*f.data)
+		if err != nil {
+			return segmentDigests{}, fmt.Errorf("unable to read %s digest: %v", f.name, err)
+		}
+	}
+	return digests, nil
+}
+
+// verifyDigests verifies the files of the provided segment data, with their digest trailers
+// already removed, match the provided digests.
+func verifyDigests(data SegmentData, digests segmentDigests) error {
+	for _, f := range segmentFiles(&data, &digests) {
+		if err := f.verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lazyDigests verifies the digests of the files of a segment which are not read when the
+// segment is created, each the first time the file is accessed.
+type lazyDigests struct {
+	docsData     lazyDigest
+	postingsData lazyDigest
+	fstTermsData lazyDigest
+}
+
+// newLazyDigests verifies the digests of the files of the provided segment data which are
+// read when the segment is created and returns a lazyDigests for the remaining files.
+func newLazyDigests(data *SegmentData, digests *segmentDigests) (*lazyDigests, error) {
+	files := segmentFiles(data, digests)
+	for _, idx := range []int{docsIdxFileIdx, fstFieldsFileIdx} {
+		if err := files[idx].verify(); err != nil {
+			return nil, err
+		}
+	}
+	return &lazyDigests{
+		docsData:     lazyDigest{file: files[docsDataFileIdx]},
+		postingsData: lazyDigest{file: files[postingsDataFileIdx]},
+		fstTermsData: lazyDigest{file: files[fstTermsFileIdx]},
+	}, nil
+}
+
+// lazyDigest verifies the digest of a file once, the first time verify is called.
+type lazyDigest struct {
+	once sync.Once
+	file segmentFile
+	err  error
+}
+
+func (d *lazyDigest) verify() error {
+	d.once.Do(func() {
+		d.err = d.file.verify()
+	})
+	return d.err
+}
+
+type segmentFile struct {
+	name   string
+	data   *[]byte
+	digest *uint32
+}
+
+func (f segmentFile) verify() error {
+	if actual := crc32.Checksum(*f.data, digestTable); actual != *f.digest {
+		return fmt.Errorf("%s digest mismatch: expected %d, actual %d", f.name, *f.digest, actual)
+	}
+	return nil
+}
+
+// The indices of each of the files in the slice returned by segmentFiles.
+const (
+	docsDataFileIdx = iota
+	docsIdxFileIdx
+	postingsDataFileIdx
+	fstTermsFileIdx
+	fstFieldsFileIdx
+)
+
+func segmentFiles(data *SegmentData, digests *segmentDigests) []segmentFile {
+	return []segmentFile{
+		{name: "documents data", data: &data.DocsData, digest: &digests.docsData},
+		{name: "documents index", data: &data.DocsIdxData, digest: &digests.docsIdxData},
+		{name: "postings data", data: &data.PostingsData, digest: &digests.postingsData},
+		{name: "fst terms", data: &data.FSTTermsData, digest: &digests.fstTermsData},
+		{name: "fst fields", data: &data.FSTFieldsData, digest: &digests.fstFieldsData},
+	}
+}
+
+// splitDigest splits a file into its contents and its digest trailer.
+func splitDigest(b []byte) ([]byte, uint32, error) {
+	if len(b) < digestLen {
+		return nil, 0, errDigestMissing
+	}
+
+	payloadEnd := len(b) - digestLen
+	digest, err := encoding.NewDecoder(b[payloadEnd:]).Uint32()
+	if err != nil {
+		return nil, 0, err
+	}
+	return b[:payloadEnd], digest, nil
+}
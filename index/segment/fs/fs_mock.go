@@ -97,7 +97,7 @@ func (mr *MockWriterMockRecorder) MinorVersion() *gomock.Call {
 }
 
 // Reset mocks base method
-func (m *MockWriter) Reset(arg0 segment.MutableSegment) error {
+func (m *MockWriter) Reset(arg0 segment.Segment) error {
 	ret := m.ctrl.Call(m, "Reset", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
@@ -229,6 +229,18 @@ func (mr *MockSegmentMockRecorder) ContainsID(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsID", reflect.TypeOf((*MockSegment)(nil).ContainsID), arg0)
 }
 
+// DeletePostings mocks base method
+func (m *MockSegment) DeletePostings(arg0 postings.List) error {
+	ret := m.ctrl.Call(m, "DeletePostings", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostings indicates an expected call of DeletePostings
+func (mr *MockSegmentMockRecorder) DeletePostings(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostings", reflect.TypeOf((*MockSegment)(nil).DeletePostings), arg0)
+}
+
 // Doc mocks base method
 func (m *MockSegment) Doc(arg0 postings.ID) (doc.Document, error) {
 	ret := m.ctrl.Call(m, "Doc", arg0)
@@ -242,10 +254,23 @@ func (mr *MockSegmentMockRecorder) Doc(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Doc", reflect.TypeOf((*MockSegment)(nil).Doc), arg0)
 }
 
+// DocFrequency mocks base method
+func (m *MockSegment) DocFrequency(arg0, arg1 []byte) (int, error) {
+	ret := m.ctrl.Call(m, "DocFrequency", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DocFrequency indicates an expected call of DocFrequency
+func (mr *MockSegmentMockRecorder) DocFrequency(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DocFrequency", reflect.TypeOf((*MockSegment)(nil).DocFrequency), arg0, arg1)
+}
+
 // Docs mocks base method
-func (m *MockSegment) Docs(arg0 postings.List) (doc.Iterator, error) {
+func (m *MockSegment) Docs(arg0 postings.List) (index.IDDocIterator, error) {
 	ret := m.ctrl.Call(m, "Docs", arg0)
-	ret0, _ := ret[0].(doc.Iterator)
+	ret0, _ := ret[0].(index.IDDocIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -255,6 +280,32 @@ func (mr *MockSegmentMockRecorder) Docs(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Docs", reflect.TypeOf((*MockSegment)(nil).Docs), arg0)
 }
 
+// FieldNames mocks base method
+func (m *MockSegment) FieldNames() ([][]byte, error) {
+	ret := m.ctrl.Call(m, "FieldNames")
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FieldNames indicates an expected call of FieldNames
+func (mr *MockSegmentMockRecorder) FieldNames() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FieldNames", reflect.TypeOf((*MockSegment)(nil).FieldNames))
+}
+
+// FieldTerms mocks base method
+func (m *MockSegment) FieldTerms(arg0 []byte) (index.TermsIterator, error) {
+	ret := m.ctrl.Call(m, "FieldTerms", arg0)
+	ret0, _ := ret[0].(index.TermsIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FieldTerms indicates an expected call of FieldTerms
+func (mr *MockSegmentMockRecorder) FieldTerms(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FieldTerms", reflect.TypeOf((*MockSegment)(nil).FieldTerms), arg0)
+}
+
 // Fields mocks base method
 func (m *MockSegment) Fields() ([][]byte, error) {
 	ret := m.ctrl.Call(m, "Fields")
@@ -281,6 +332,32 @@ func (mr *MockSegmentMockRecorder) MatchAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchAll", reflect.TypeOf((*MockSegment)(nil).MatchAll))
 }
 
+// MatchField mocks base method
+func (m *MockSegment) MatchField(arg0 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchField", arg0)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchField indicates an expected call of MatchField
+func (mr *MockSegmentMockRecorder) MatchField(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchField", reflect.TypeOf((*MockSegment)(nil).MatchField), arg0)
+}
+
+// MatchPrefix mocks base method
+func (m *MockSegment) MatchPrefix(arg0, arg1 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchPrefix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchPrefix indicates an expected call of MatchPrefix
+func (mr *MockSegmentMockRecorder) MatchPrefix(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchPrefix", reflect.TypeOf((*MockSegment)(nil).MatchPrefix), arg0, arg1)
+}
+
 // MatchRegexp mocks base method
 func (m *MockSegment) MatchRegexp(arg0, arg1 []byte, arg2 *regexp.Regexp) (postings.List, error) {
 	ret := m.ctrl.Call(m, "MatchRegexp", arg0, arg1, arg2)
@@ -307,6 +384,32 @@ func (mr *MockSegmentMockRecorder) MatchTerm(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerm", reflect.TypeOf((*MockSegment)(nil).MatchTerm), arg0, arg1)
 }
 
+// MatchTermRange mocks base method
+func (m *MockSegment) MatchTermRange(arg0, arg1, arg2 []byte, arg3, arg4 bool) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchTermRange", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTermRange indicates an expected call of MatchTermRange
+func (mr *MockSegmentMockRecorder) MatchTermRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTermRange", reflect.TypeOf((*MockSegment)(nil).MatchTermRange), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MatchTerms mocks base method
+func (m *MockSegment) MatchTerms(arg0 []byte, arg1 [][]byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "MatchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchTerms indicates an expected call of MatchTerms
+func (mr *MockSegmentMockRecorder) MatchTerms(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchTerms", reflect.TypeOf((*MockSegment)(nil).MatchTerms), arg0, arg1)
+}
+
 // Reader mocks base method
 func (m *MockSegment) Reader() (index.Reader, error) {
 	ret := m.ctrl.Call(m, "Reader")
@@ -344,3 +447,15 @@ func (m *MockSegment) Terms(arg0 []byte) ([][]byte, error) {
 func (mr *MockSegmentMockRecorder) Terms(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terms", reflect.TypeOf((*MockSegment)(nil).Terms), arg0)
 }
+
+// Verify mocks base method
+func (m *MockSegment) Verify() error {
+	ret := m.ctrl.Call(m, "Verify")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify
+func (mr *MockSegmentMockRecorder) Verify() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSegment)(nil).Verify))
+}
@@ -0,0 +1,665 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index/segment/fs/encoding"
+	"github.com/m3db/m3ninx/index/segment/fs/encoding/docs"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/pilosa"
+	"github.com/m3db/m3ninx/postings/roaring"
+
+	"github.com/couchbase/vellum"
+)
+
+var (
+	errMergeUnsupportedSegment = errors.New("only segments created by NewSegment can be merged")
+)
+
+// mergeSource is a segment being merged along with the postings IDs of its documents
+// which are included in the merged segment.
+type mergeSource struct {
+	segment *fsSegment
+
+	// The postings IDs of the documents included in the merged segment, both as a
+	// postings list and in ascending order.
+	live postings.MutableList
+	ids  []postings.ID
+
+	// The postings ID in the merged segment of the first included document.
+	base postings.ID
+}
+
+// remap returns the postings ID in the merged segment of the document with the given
+// postings ID. The document must be included in the merged segment.
+func (s *mergeSource) remap(id postings.ID) postings.ID {
+	idx := sort.Search(len(s.ids), func(i int) bool {
+		return s.ids[i] >= id
+	})
+	return s.base + postings.ID(idx)
+}
+
+// mergedField is a field in the merged segment along with its terms, in order, and the
+// offset of each term's postings list.
+type mergedField struct {
+	name                []byte
+	terms               [][]byte
+	postingsOffsets     []uint64
+	fieldPostingsOffset uint64
+	fstTermsOffset      uint64
+}
+
+type mergeWriter struct {
+	opts    WriterOpts
+	sources []*mergeSource
+
+	intEncoder      *encoding.Encoder
+	postingsEncoder *pilosa.Encoder
+	fstWriter       *fstWriter
+	docDataWriter   *docs.DataWriter
+	docIndexWriter  *docs.IndexWriter
+
+	metadata            []byte
+	docsDataFileWritten bool
+	postingsFileWritten bool
+	fstTermsFileWritten bool
+	fields              []mergedField
+	docOffsets          []docOffset
+}
+
+// NewMergeWriter returns a new MergeWriter.
+func NewMergeWriter(opts WriterOpts) MergeWriter {
+	return &mergeWriter{
+		opts:            opts,
+		intEncoder:      encoding.NewEncoder(defaultInitialIntEncoderSize),
+		postingsEncoder: pilosa.NewEncoder(),
+		fstWriter:       newFSTWriter(),
+		docDataWriter:   docs.NewDataWriter(nil),
+		docIndexWriter:  docs.NewIndexWriter(nil),
+		docOffsets:      make([]docOffset, 0, defaultInitialDocOffsetsSize),
+	}
+}
+
+func (w *mergeWriter) clear() {
+	w.sources = nil
+
+	w.fstWriter = newFSTWriter()
+	w.intEncoder.Reset()
+	w.postingsEncoder.Reset()
+	w.docDataWriter.Reset(nil)
+	w.docIndexWriter.Reset(nil)
+
+	w.metadata = nil
+	w.docsDataFileWritten = false
+	w.postingsFileWritten = false
+	w.fstTermsFileWritten = false
+	w.fields = nil
+	w.docOffsets = w.docOffsets[:0]
+}
+
+func (w *mergeWriter) Reset(segs []Segment) error {
+	w.clear()
+
+	sources := make([]*mergeSource, 0, len(segs))
+	for _, s := range segs {
+		seg, ok := s.(*fsSegment)
+		if !ok {
+			return errMergeUnsupportedSegment
+		}
+
+		// Deleted documents are excluded from the merged segment.
+		live, err := seg.MatchAll()
+		if err != nil {
+			return err
+		}
+		sources = append(sources, &mergeSource{
+			segment: seg,
+			live:    live,
+		})
+	}
+
+	w.sources = sources
+	if err := w.rlockSources(); err != nil {
+		w.sources = nil
+		return err
+	}
+	err := w.excludeDuplicateIDsWithRLock()
+	w.runlockSources()
+	if err != nil {
+		w.sources = nil
+		return err
+	}
+
+	// Assign the documents in the merged segment contiguous postings IDs in the order of
+	// the segments.
+	var base postings.ID
+	for _, src := range w.sources {
+		src.base = base
+		iter := src.live.Iterator()
+		for iter.Next() {
+			src.ids = append(src.ids, iter.Current())
+		}
+		if err := iter.Err(); err != nil {
+			w.sources = nil
+			return err
+		}
+		if err := iter.Close(); err != nil {
+			w.sources = nil
+			return err
+		}
+		base += postings.ID(len(src.ids))
+	}
+
+	metadata := defaultV1Metadata()
+	metadata.NumDocs = int64(base)
+	metadata.FieldPostings = w.opts.FieldPostingsLists
+	metadata.DocFrequencies = w.opts.DocFrequencies
+	metadata.FileDigests = w.opts.FileDigests
+	metadataBytes, err := metadata.Marshal()
+	if err != nil {
+		w.sources = nil
+		return err
+	}
+	w.metadata = metadataBytes
+	return nil
+}
+
+// excludeDuplicateIDsWithRLock excludes a document from the merged segment if a prior
+// segment contains a document with the same ID. It must be called with the read lock
+// of each of the sources.
+func (w *mergeWriter) excludeDuplicateIDsWithRLock() error {
+	fsts := make([]*vellum.FST, len(w.sources))
+	defer closeFSTs(fsts)
+
+	for i, src := range w.sources {
+		fst, exists, err := src.segment.retrieveTermsFSTWithRLock(doc.IDReservedFieldName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			fsts[i] = fst
+		}
+	}
+
+	duplicates := make([]postings.MutableList, len(w.sources))
+	err := mergeFSTs(fsts, func(_ []byte, values []fstValue) error {
+		seen := false
+		for _, v := range values {
+			src := w.sources[v.source]
+			pl, err := src.segment.retrievePostingsListWithRLock(v.value)
+			if err != nil {
+				return err
+			}
+
+			iter := pl.Iterator()
+			for iter.Next() {
+				id := iter.Current()
+				if !src.live.Contains(id) {
+					continue
+				}
+				if seen {
+					if duplicates[v.source] == nil {
+						duplicates[v.source] = roaring.NewPostingsList()
+					}
+					duplicates[v.source].Insert(id)
+				}
+				seen = true
+			}
+			if err := iter.Err(); err != nil {
+				iter.Close()
+				return err
+			}
+			if err := iter.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, src := range w.sources {
+		if duplicates[i] == nil {
+			continue
+		}
+		if err := src.live.Difference(duplicates[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *mergeWriter) MajorVersion() int {
+	return MajorVersion
+}
+
+func (w *mergeWriter) MinorVersion() int {
+	return writerMinorVersion(w.opts)
+}
+
+func (w *mergeWriter) Metadata() []byte {
+	return w.metadata
+}
+
+func (w *mergeWriter) WriteDocumentsData(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeDocumentsData)
+}
+
+func (w *mergeWriter) writeDocumentsData(iow io.Writer) error {
+	if err := w.rlockSources(); err != nil {
+		return err
+	}
+	defer w.runlockSources()
+
+	w.docDataWriter.Reset(iow)
+
+	var currOffset uint64
+	for _, src := range w.sources {
+		for i, id := range src.ids {
+			offset, err := src.segment.docsIndexReader.Read(id)
+			if err != nil {
+				return err
+			}
+			d, err := src.segment.readDocWithRLock(offset)
+			if err != nil {
+				return err
+			}
+
+			n, err := w.docDataWriter.Write(d)
+			if err != nil {
+				return err
+			}
+			w.docOffsets = append(w.docOffsets, docOffset{
+				ID:     src.base + postings.ID(i),
+				offset: currOffset,
+			})
+			currOffset += uint64(n)
+		}
+	}
+
+	w.docsDataFileWritten = true
+	return nil
+}
+
+func (w *mergeWriter) WriteDocumentsIndex(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeDocumentsIndex)
+}
+
+func (w *mergeWriter) writeDocumentsIndex(iow io.Writer) error {
+	if !w.docsDataFileWritten {
+		return fmt.Errorf("documents data file has to be written before documents index file")
+	}
+
+	w.docIndexWriter.Reset(iow)
+
+	for _, do := range w.docOffsets {
+		if err := w.docIndexWriter.Write(do.ID, do.offset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *mergeWriter) WritePostingsOffsets(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writePostingsOffsets)
+}
+
+func (w *mergeWriter) writePostingsOffsets(iow io.Writer) error {
+	if err := w.rlockSources(); err != nil {
+		return err
+	}
+	defer w.runlockSources()
+
+	fieldsFSTs := make([]*vellum.FST, len(w.sources))
+	for i, src := range w.sources {
+		fieldsFSTs[i] = src.segment.fieldsFST
+	}
+
+	var (
+		currentOffset uint64
+		fields        []mergedField
+	)
+	err := mergeFSTs(fieldsFSTs, func(name []byte, values []fstValue) error {
+		termsFSTs := make([]*vellum.FST, len(w.sources))
+		defer closeFSTs(termsFSTs)
+
+		for _, v := range values {
+			fst, err := w.sources[v.source].segment.loadTermsFSTWithRLock(v.value)
+			if err != nil {
+				return err
+			}
+			termsFSTs[v.source] = fst
+		}
+
+		field := mergedField{
+			name: copyBytes(name),
+		}
+
+		// the union of the postings lists of the field's terms, only tracked if the
+		// field's postings list is written
+		var fieldPl postings.MutableList
+		if w.opts.FieldPostingsLists {
+			fieldPl = roaring.NewPostingsList()
+		}
+
+		err := mergeFSTs(termsFSTs, func(term []byte, values []fstValue) error {
+			pl, err := w.mergePostingsListsWithRLock(values)
+			if err != nil {
+				return err
+			}
+
+			// skip terms which only appear in excluded documents
+			if pl.IsEmpty() {
+				return nil
+			}
+
+			n, err := writeTermPostingsList(iow, w.intEncoder, w.postingsEncoder, pl, w.opts.DocFrequencies)
+			if err != nil {
+				return err
+			}
+			currentOffset += n
+
+			field.terms = append(field.terms, copyBytes(term))
+			field.postingsOffsets = append(field.postingsOffsets, currentOffset)
+
+			if fieldPl != nil {
+				return fieldPl.Union(pl)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// skip fields which only appear in excluded documents
+		if len(field.terms) == 0 {
+			return nil
+		}
+
+		if fieldPl != nil {
+			n, err := writePostingsList(iow, w.intEncoder, w.postingsEncoder, fieldPl)
+			if err != nil {
+				return err
+			}
+			currentOffset += n
+			field.fieldPostingsOffset = currentOffset
+		}
+
+		fields = append(fields, field)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	w.fields = fields
+	w.postingsFileWritten = true
+	return nil
+}
+
+// mergePostingsListsWithRLock returns the union of the provided postings lists of the
+// sources, remapped to the postings IDs of the merged segment. It must be called with
+// the read lock of each of the sources.
+func (w *mergeWriter) mergePostingsListsWithRLock(values []fstValue) (postings.List, error) {
+	merged := roaring.NewPostingsList()
+	for _, v := range values {
+		src := w.sources[v.source]
+		pl, err := src.segment.retrievePostingsListWithRLock(v.value)
+		if err != nil {
+			return nil, err
+		}
+
+		iter := pl.Iterator()
+		for iter.Next() {
+			id := iter.Current()
+			if src.live.Contains(id) {
+				merged.Insert(src.remap(id))
+			}
+		}
+		if err := iter.Err(); err != nil {
+			iter.Close()
+			return nil, err
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+func (w *mergeWriter) WriteFSTTerms(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeFSTTerms)
+}
+
+func (w *mergeWriter) writeFSTTerms(iow io.Writer) error {
+	if !w.postingsFileWritten {
+		return fmt.Errorf("postings offsets have to be written before fst terms can be written")
+	}
+
+	currentOffset := uint64(0)
+	for i := range w.fields {
+		field := &w.fields[i]
+
+		// the offset of the field's postings list precedes the field's fst
+		if w.opts.FieldPostingsLists {
+			n, err := writeUint64AndSizeAndMagicNumber(iow, w.intEncoder, field.fieldPostingsOffset)
+			if err != nil {
+				return err
+			}
+			currentOffset += n
+		}
+
+		if err := w.fstWriter.Reset(iow); err != nil {
+			return err
+		}
+
+		for j, t := range field.terms {
+			if err := w.fstWriter.Add(t, field.postingsOffsets[j]); err != nil {
+				return err
+			}
+		}
+
+		numBytesFST, err := w.fstWriter.Close()
+		if err != nil {
+			return err
+		}
+
+		n, err := writeSizeAndMagicNumber(iow, w.intEncoder, numBytesFST)
+		if err != nil {
+			return err
+		}
+
+		currentOffset += numBytesFST + n
+		field.fstTermsOffset = currentOffset
+	}
+
+	w.fstTermsFileWritten = true
+	return nil
+}
+
+func (w *mergeWriter) WriteFSTFields(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeFSTFields)
+}
+
+func (w *mergeWriter) writeFSTFields(iow io.Writer) error {
+	if !w.fstTermsFileWritten {
+		return fmt.Errorf("fst terms files have to be written before fst fields can be written")
+	}
+
+	if err := w.fstWriter.Reset(iow); err != nil {
+		return err
+	}
+
+	for _, field := range w.fields {
+		if err := w.fstWriter.Add(field.name, field.fstTermsOffset); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.fstWriter.Close()
+	return err
+}
+
+// rlockSources acquires the read lock of each of the sources, returning an error if
+// any of them have been closed.
+func (w *mergeWriter) rlockSources() error {
+	for i, src := range w.sources {
+		src.segment.RLock()
+		if src.segment.closed {
+			for _, locked := range w.sources[:i+1] {
+				locked.segment.RUnlock()
+			}
+			return errReaderClosed
+		}
+	}
+	return nil
+}
+
+func (w *mergeWriter) runlockSources() {
+	for _, src := range w.sources {
+		src.segment.RUnlock()
+	}
+}
+
+// fstValue is the value of a key in one of the FSTs being merged.
+type fstValue struct {
+	source int
+	value  uint64
+}
+
+// mergeFSTs calls fn with each of the keys in the provided FSTs in order, along with
+// the values of the key in each of the FSTs which contain it ordered by the index of
+// the FST. Nil FSTs are skipped. The key is only valid for the duration of the call.
+func mergeFSTs(fsts []*vellum.FST, fn func(key []byte, values []fstValue) error) error {
+	h := make(fstIterHeap, 0, len(fsts))
+	defer func() {
+		for _, it := range h {
+			it.iter.Close()
+		}
+	}()
+
+	for i, fst := range fsts {
+		if fst == nil {
+			continue
+		}
+		iter, err := fst.Iterator(minByteKey, nil)
+		if err == vellum.ErrIteratorDone {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		key, value := iter.Current()
+		h = append(h, &fstIter{source: i, iter: iter, key: key, value: value})
+	}
+	heap.Init(&h)
+
+	var (
+		key    []byte
+		values = make([]fstValue, 0, len(fsts))
+	)
+	for h.Len() > 0 {
+		key = append(key[:0], h[0].key...)
+		values = values[:0]
+
+		for h.Len() > 0 && bytes.Equal(h[0].key, key) {
+			it := h[0]
+			values = append(values, fstValue{source: it.source, value: it.value})
+
+			err := it.iter.Next()
+			if err == vellum.ErrIteratorDone {
+				heap.Pop(&h)
+				if err := it.iter.Close(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			it.key, it.value = it.iter.Current()
+			heap.Fix(&h, 0)
+		}
+
+		if err := fn(key, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fstIter struct {
+	source int
+	iter   vellum.Iterator
+	key    []byte
+	value  uint64
+}
+
+// fstIterHeap is a min-heap of FST iterators ordered by their current key and then by
+// the index of their FST.
+type fstIterHeap []*fstIter
+
+func (h fstIterHeap) Len() int { return len(h) }
+
+func (h fstIterHeap) Less(i, j int) bool {
+	if c := bytes.Compare(h[i].key, h[j].key); c != 0 {
+		return c < 0
+	}
+	return h[i].source < h[j].source
+}
+
+func (h fstIterHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *fstIterHeap) Push(x interface{}) {
+	*h = append(*h, x.(*fstIter))
+}
+
+func (h *fstIterHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	it := old[n-1]
+	*h = old[:n-1]
+	return it
+}
+
+func closeFSTs(fsts []*vellum.FST) {
+	for _, fst := range fsts {
+		if fst != nil {
+			fst.Close()
+		}
+	}
+}
+
+func copyBytes(b []byte) []byte {
+	copied := make([]byte, len(b))
+	copy(copied, b)
+	return copied
+}
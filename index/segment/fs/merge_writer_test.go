@@ -0,0 +1,187 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/m3db/m3ninx/doc"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeWriter(t *testing.T) {
+	_, expected := newTestSegments(t, lotsTestDocuments)
+
+	var (
+		third = len(lotsTestDocuments) / 3
+		segs  []Segment
+	)
+	for _, docs := range [][]doc.Document{
+		lotsTestDocuments[:third],
+		lotsTestDocuments[third : 2*third],
+		lotsTestDocuments[2*third:],
+	} {
+		_, s := newTestSegments(t, docs)
+		segs = append(segs, s.(Segment))
+	}
+
+	w := NewMergeWriter(WriterOpts{})
+	require.NoError(t, w.Reset(segs))
+	merged := newSegmentFromWriter(t, w)
+	require.Equal(t, expected.Size(), merged.Size())
+
+	expectedFields, err := expected.Fields()
+	require.NoError(t, err)
+	mergedFields, err := merged.Fields()
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, expectedFields, mergedFields)
+
+	expectedReader, err := expected.Reader()
+	require.NoError(t, err)
+	mergedReader, err := merged.Reader()
+	require.NoError(t, err)
+
+	for _, f := range expectedFields {
+		expectedTerms, err := expected.Terms(f)
+		require.NoError(t, err)
+		mergedTerms, err := merged.Terms(f)
+		require.NoError(t, err)
+		assertSliceOfByteSlicesEqual(t, expectedTerms, mergedTerms)
+
+		for _, term := range expectedTerms {
+			expectedPL, err := expectedReader.MatchTerm(f, term)
+			require.NoError(t, err)
+			mergedPL, err := mergedReader.MatchTerm(f, term)
+			require.NoError(t, err)
+			require.True(t, expectedPL.Equal(mergedPL), "%s=%s", f, term)
+		}
+	}
+
+	// The documents are written in the order of the merged segments.
+	expectedIter, err := expectedReader.AllDocs()
+	require.NoError(t, err)
+	expectedDocs, err := collectDocs(expectedIter)
+	require.NoError(t, err)
+	mergedIter, err := mergedReader.AllDocs()
+	require.NoError(t, err)
+	mergedDocs, err := collectDocs(mergedIter)
+	require.NoError(t, err)
+	require.Equal(t, expectedDocs, mergedDocs)
+
+	require.NoError(t, expectedReader.Close())
+	require.NoError(t, mergedReader.Close())
+}
+
+func TestMergeWriterDropsDeletedAndDuplicateDocuments(t *testing.T) {
+	_, first := newTestSegments(t, fewTestDocuments[2:])
+
+	duplicate := doc.Document{
+		ID: fewTestDocuments[2].ID,
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("kiwi"),
+			},
+		},
+	}
+	_, second := newTestSegments(t, append([]doc.Document{duplicate}, fewTestDocuments[:2]...))
+
+	r, err := second.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.NoError(t, second.DeletePostings(pl))
+	require.NoError(t, r.Close())
+
+	w := NewMergeWriter(WriterOpts{})
+	require.NoError(t, w.Reset([]Segment{first.(Segment), second.(Segment)}))
+	merged := newSegmentFromWriter(t, w)
+	require.Equal(t, int64(2), merged.Size())
+
+	terms, err := merged.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, [][]byte{[]byte("banana"), []byte("pineapple")}, terms)
+
+	terms, err = merged.Terms([]byte("color"))
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, [][]byte{[]byte("yellow")}, terms)
+
+	// The postings IDs of the remaining documents are contiguous.
+	mergedReader, err := merged.Reader()
+	require.NoError(t, err)
+	pl, err = mergedReader.MatchTerm([]byte("fruit"), []byte("banana"))
+	require.NoError(t, err)
+	require.True(t, pl.Contains(1))
+
+	iter, err := mergedReader.AllDocs()
+	require.NoError(t, err)
+	actual, err := collectDocs(iter)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, fewTestDocuments[2], actual[0])
+	require.Equal(t, fewTestDocuments[0].Fields, actual[1].Fields)
+	require.NoError(t, mergedReader.Close())
+}
+
+func TestMergeWriterMaxRuneTerm(t *testing.T) {
+	// The term sorts at the largest key of a valid UTF-8 string and must not be dropped
+	// by the merge.
+	var (
+		field = []byte("fruit")
+		term  = []byte(string(utf8.MaxRune))
+		docs  = []doc.Document{
+			doc.Document{
+				ID: []byte("1"),
+				Fields: []doc.Field{
+					doc.Field{Name: field, Value: []byte("apple")},
+				},
+			},
+			doc.Document{
+				ID: []byte("2"),
+				Fields: []doc.Field{
+					doc.Field{Name: field, Value: term},
+				},
+			},
+		}
+	)
+	_, first := newTestSegments(t, docs[:1])
+	_, second := newTestSegments(t, docs[1:])
+
+	w := NewMergeWriter(WriterOpts{})
+	require.NoError(t, w.Reset([]Segment{first.(Segment), second.(Segment)}))
+	merged := newSegmentFromWriter(t, w)
+	require.Equal(t, int64(2), merged.Size())
+
+	r, err := merged.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm(field, term)
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	require.True(t, pl.Contains(1))
+	require.NoError(t, r.Close())
+}
+
+func TestMergeWriterUnsupportedSegment(t *testing.T) {
+	w := NewMergeWriter(WriterOpts{})
+	require.Equal(t, errMergeUnsupportedSegment, w.Reset([]Segment{nil}))
+}
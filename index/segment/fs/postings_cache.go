@@ -0,0 +1,251 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/uber-go/tally"
+)
+
+// nextSegmentID is used to assign each segment a unique ID for keying the postings list cache.
+var nextSegmentID uint64
+
+func newSegmentID() uint64 {
+	return atomic.AddUint64(&nextSegmentID, 1)
+}
+
+// PostingsListCacheOpts represent the collection of knobs used by the PostingsListCache.
+type PostingsListCacheOpts struct {
+	// MaxBytes is the maximum estimated size in bytes of the cached postings lists.
+	MaxBytes uint64
+
+	// InstrumentOptions are the options used to emit the cache hit and miss metrics.
+	InstrumentOptions instrument.Options
+}
+
+type patternType int
+
+const (
+	termPatternType patternType = iota
+	regexpPatternType
+)
+
+var patternTypes = []patternType{
+	termPatternType,
+	regexpPatternType,
+}
+
+func (t patternType) String() string {
+	switch t {
+	case termPatternType:
+		return "term"
+	case regexpPatternType:
+		return "regexp"
+	default:
+		return "unknown"
+	}
+}
+
+// PostingsListCache is an LRU cache of the postings lists retrieved by matching terms and
+// regular expressions against FST segments. It is sized by the estimated number of bytes
+// used by the cached postings lists and may be shared by several segments. The entries of
+// a segment are removed when it is closed. It is safe for concurrent access.
+type PostingsListCache struct {
+	sync.Mutex
+
+	maxBytes uint64
+	bytes    uint64
+	lru      *list.List
+	entries  map[postingsListCacheKey]*list.Element
+	metrics  map[patternType]postingsListCacheMetrics
+}
+
+type postingsListCacheKey struct {
+	segmentID   uint64
+	field       string
+	pattern     string
+	patternType patternType
+}
+
+type postingsListCacheEntry struct {
+	key  postingsListCacheKey
+	pl   postings.List
+	size uint64
+}
+
+type postingsListCacheMetrics struct {
+	hits   tally.Counter
+	misses tally.Counter
+}
+
+// NewPostingsListCache returns a new PostingsListCache.
+func NewPostingsListCache(opts PostingsListCacheOpts) *PostingsListCache {
+	iopts := opts.InstrumentOptions
+	if iopts == nil {
+		iopts = instrument.NewOptions()
+	}
+
+	scope := iopts.MetricsScope().SubScope("postings-list-cache")
+	metrics := make(map[patternType]postingsListCacheMetrics, len(patternTypes))
+	for _, t := range patternTypes {
+		tagged := scope.Tagged(map[string]string{"pattern-type": t.String()})
+		metrics[t] = postingsListCacheMetrics{
+			hits:   tagged.Counter("hits"),
+			misses: tagged.Counter("misses"),
+		}
+	}
+
+	return &PostingsListCache{
+		maxBytes: opts.MaxBytes,
+		lru:      list.New(),
+		entries:  make(map[postingsListCacheKey]*list.Element),
+		metrics:  metrics,
+	}
+}
+
+// get returns the cached postings list for the provided pattern of a segment.
+func (c *PostingsListCache) get(
+	segmentID uint64,
+	field, pattern []byte,
+	t patternType,
+) (postings.List, bool) {
+	key := newPostingsListCacheKey(segmentID, field, pattern, t)
+
+	c.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.Unlock()
+
+	if !ok {
+		c.metrics[t].misses.Inc(1)
+		return nil, false
+	}
+	c.metrics[t].hits.Inc(1)
+	return elem.Value.(*postingsListCacheEntry).pl, true
+}
+
+// peek returns the postings list for the provided pattern of a segment if it is in the
+// cache, without counting it as a hit or miss or marking the entry as recently used.
+func (c *PostingsListCache) peek(
+	segmentID uint64,
+	field, pattern []byte,
+	t patternType,
+) (postings.List, bool) {
+	key := newPostingsListCacheKey(segmentID, field, pattern, t)
+
+	c.Lock()
+	defer c.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return elem.Value.(*postingsListCacheEntry).pl, true
+}
+
+// put adds the postings list for the provided pattern of a segment to the cache, evicting
+// the least recently used entries if the cache is full. The postings list must not be
+// modified once it has been added to the cache.
+func (c *PostingsListCache) put(
+	segmentID uint64,
+	field, pattern []byte,
+	t patternType,
+	pl postings.List,
+) {
+	size := postingsListSizeInBytes(pl) + uint64(len(field)+len(pattern))
+	if size > c.maxBytes {
+		return
+	}
+
+	key := newPostingsListCacheKey(segmentID, field, pattern, t)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		// The postings list was added concurrently by another caller.
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	for c.bytes+size > c.maxBytes {
+		c.removeWithLock(c.lru.Back())
+	}
+
+	c.entries[key] = c.lru.PushFront(&postingsListCacheEntry{
+		key:  key,
+		pl:   pl,
+		size: size,
+	})
+	c.bytes += size
+}
+
+// purgeSegment removes all of the cached postings lists of a segment.
+func (c *PostingsListCache) purgeSegment(segmentID uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*postingsListCacheEntry).key.segmentID == segmentID {
+			c.removeWithLock(elem)
+		}
+		elem = next
+	}
+}
+
+func (c *PostingsListCache) removeWithLock(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*postingsListCacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
+
+func newPostingsListCacheKey(
+	segmentID uint64,
+	field, pattern []byte,
+	t patternType,
+) postingsListCacheKey {
+	return postingsListCacheKey{
+		segmentID:   segmentID,
+		field:       string(field),
+		pattern:     string(pattern),
+		patternType: t,
+	}
+}
+
+// postingsListSizeInBytes returns an estimate of the number of bytes used by a postings list.
+func postingsListSizeInBytes(pl postings.List) uint64 {
+	if size, ok := roaring.SizeInBytes(pl); ok {
+		return size
+	}
+
+	// Assume each postings ID is stored as a uint32 if the implementation is unknown.
+	const sizeofUint32 = 4
+	return uint64(pl.Len()) * sizeofUint32
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+	"github.com/m3db/m3x/instrument"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+)
+
+func TestPostingsListCache(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	pl := newTestPostingsList(0, 100)
+	size := postingsListSizeInBytes(pl) + uint64(len("fruit")+len("apple"))
+
+	// The cache has room for exactly two of the postings lists.
+	cache := NewPostingsListCache(PostingsListCacheOpts{
+		MaxBytes:          2 * size,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+	})
+
+	field := []byte("fruit")
+	_, ok := cache.get(1, field, []byte("apple"), termPatternType)
+	require.False(t, ok)
+
+	cache.put(1, field, []byte("apple"), termPatternType, pl)
+	cache.put(1, field, []byte("grape"), termPatternType, newTestPostingsList(0, 100))
+
+	// Retrieving the first postings list makes it the most recently used.
+	cached, ok := cache.get(1, field, []byte("apple"), termPatternType)
+	require.True(t, ok)
+	require.True(t, pl == cached)
+
+	// The same pattern is cached separately for each segment and pattern type.
+	_, ok = cache.get(2, field, []byte("apple"), termPatternType)
+	require.False(t, ok)
+	_, ok = cache.get(1, field, []byte("apple"), regexpPatternType)
+	require.False(t, ok)
+
+	// Adding a third postings list evicts the least recently used.
+	cache.put(1, field, []byte("lemon"), termPatternType, newTestPostingsList(0, 100))
+	_, ok = cache.get(1, field, []byte("grape"), termPatternType)
+	require.False(t, ok)
+	_, ok = cache.get(1, field, []byte("apple"), termPatternType)
+	require.True(t, ok)
+	_, ok = cache.get(1, field, []byte("lemon"), termPatternType)
+	require.True(t, ok)
+
+	// Postings lists larger than the cache are never cached.
+	cache.put(1, field, []byte("melon"), termPatternType, newTestPostingsList(0, 1<<20))
+	_, ok = cache.get(1, field, []byte("melon"), termPatternType)
+	require.False(t, ok)
+	require.Equal(t, 2, cache.lru.Len())
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(3), counters["postings-list-cache.hits+pattern-type=term"].Value())
+	require.Equal(t, int64(4), counters["postings-list-cache.misses+pattern-type=term"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.misses+pattern-type=regexp"].Value())
+}
+
+func TestPostingsListCachePurgeSegment(t *testing.T) {
+	cache := NewPostingsListCache(PostingsListCacheOpts{MaxBytes: 1 << 20})
+
+	field, term := []byte("fruit"), []byte("apple")
+	cache.put(1, field, term, termPatternType, newTestPostingsList(0, 10))
+	cache.put(1, field, term, regexpPatternType, newTestPostingsList(0, 10))
+	cache.put(2, field, term, termPatternType, newTestPostingsList(0, 10))
+
+	cache.purgeSegment(1)
+	_, ok := cache.get(1, field, term, termPatternType)
+	require.False(t, ok)
+	_, ok = cache.get(1, field, term, regexpPatternType)
+	require.False(t, ok)
+	_, ok = cache.get(2, field, term, termPatternType)
+	require.True(t, ok)
+	require.Equal(t, 1, cache.lru.Len())
+	require.Equal(t, cache.entries[newPostingsListCacheKey(2, field, term, termPatternType)].Value.(*postingsListCacheEntry).size, cache.bytes)
+}
+
+func TestSegmentPostingsListCache(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	cache := NewPostingsListCache(PostingsListCacheOpts{
+		MaxBytes:          1 << 20,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+	})
+
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err := memSeg.Seal()
+	require.NoError(t, err)
+	w := NewWriter()
+	require.NoError(t, w.Reset(memSeg))
+	seg := newSegmentFromWriterWithOpts(t, w, NewSegmentOpts{
+		PostingsListPool:  postings.NewPool(nil, roaring.NewPostingsList),
+		PostingsListCache: cache,
+	}).(Segment)
+
+	field, term, regexp := []byte("fruit"), []byte("apple"), []byte("a.*")
+	for i := 0; i < 2; i++ {
+		_, err := seg.MatchTerm(field, term)
+		require.NoError(t, err)
+		_, err = seg.MatchRegexp(field, regexp, nil)
+		require.NoError(t, err)
+	}
+
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(1), counters["postings-list-cache.hits+pattern-type=term"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.misses+pattern-type=term"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.hits+pattern-type=regexp"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.misses+pattern-type=regexp"].Value())
+
+	// Deleted documents are excluded from the cached postings lists.
+	pl, err := seg.MatchTerm(field, term)
+	require.NoError(t, err)
+	require.False(t, pl.IsEmpty())
+	require.NoError(t, seg.DeletePostings(pl))
+	pl, err = seg.MatchTerm(field, term)
+	require.NoError(t, err)
+	require.True(t, pl.IsEmpty())
+
+	// Closing the segment removes its postings lists from the cache.
+	require.Equal(t, 2, cache.lru.Len())
+	require.NoError(t, seg.Close())
+	require.Equal(t, 0, cache.lru.Len())
+	require.Equal(t, uint64(0), cache.bytes)
+}
+
+func newTestPostingsList(min, max postings.ID) postings.List {
+	pl := roaring.NewPostingsList()
+	pl.AddRange(min, max)
+	return pl
+}
+
+func TestSegmentDocFrequencyCachedPostingsList(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	cache := NewPostingsListCache(PostingsListCacheOpts{
+		MaxBytes:          1 << 20,
+		InstrumentOptions: instrument.NewOptions().SetMetricsScope(scope),
+	})
+
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err := memSeg.Seal()
+	require.NoError(t, err)
+	w := NewWriter()
+	require.NoError(t, w.Reset(memSeg))
+	seg := newSegmentFromWriterWithOpts(t, w, NewSegmentOpts{
+		PostingsListPool:  postings.NewPool(nil, roaring.NewPostingsList),
+		PostingsListCache: cache,
+	}).(Segment)
+
+	// The document frequencies were not written so the document frequency of a term is only
+	// available once its postings list has been cached.
+	field, term := []byte("fruit"), []byte("apple")
+	_, err = seg.DocFrequency(field, term)
+	require.Equal(t, index.ErrDocFrequencyUnavailable, err)
+
+	pl, err := seg.MatchTerm(field, term)
+	require.NoError(t, err)
+	freq, err := seg.DocFrequency(field, term)
+	require.NoError(t, err)
+	require.Equal(t, pl.Len(), freq)
+
+	// Estimates are not counted as cache hits or misses.
+	counters := scope.Snapshot().Counters()
+	require.Equal(t, int64(0), counters["postings-list-cache.hits+pattern-type=term"].Value())
+	require.Equal(t, int64(1), counters["postings-list-cache.misses+pattern-type=term"].Value())
+
+	require.NoError(t, seg.Close())
+}
@@ -21,6 +21,7 @@
 package fs
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -46,6 +47,7 @@ import (
 var (
 	errReaderClosed            = errors.New("segment is closed")
 	errUnsupportedMajorVersion = errors.New("unsupported major version")
+	errUnsupportedMinorVersion = errors.New("unsupported minor version")
 	errDocumentsDataUnset      = errors.New("documents data bytes are not set")
 	errDocumentsIdxUnset       = errors.New("documents index bytes are not set")
 	errPostingsDataUnset       = errors.New("postings data bytes are not set")
@@ -75,6 +77,10 @@ func (sd SegmentData) Validate() error {
 		return errUnsupportedMajorVersion
 	}
 
+	if sd.MinorVersion > MinorVersion {
+		return errUnsupportedMinorVersion
+	}
+
 	if sd.DocsData == nil {
 		return errDocumentsDataUnset
 	}
@@ -101,6 +107,21 @@ func (sd SegmentData) Validate() error {
 // NewSegmentOpts represent the collection of knobs used by the Segment.
 type NewSegmentOpts struct {
 	PostingsListPool postings.Pool
+
+	// VerifyDigests sets whether the digests of the segment's files are verified when the
+	// segment is created. Otherwise they are only verified by Verify, unless
+	// LazyVerifyDigests is set.
+	VerifyDigests bool
+
+	// LazyVerifyDigests sets whether the digest of each of the segment's files is verified
+	// the first time the file is accessed rather than when the segment is created. The
+	// fields FST and documents index are accessed when the segment is created so their
+	// digests are always verified then. It has no effect if VerifyDigests is set.
+	LazyVerifyDigests bool
+
+	// PostingsListCache caches the postings lists of terms and regular expressions matched
+	// against the segment. It may be shared by several segments and is disabled if nil.
+	PostingsListCache *PostingsListCache
 }
 
 // NewSegment returns a new Segment backed by the provided options.
@@ -120,6 +141,34 @@ func NewSegment(data SegmentData, opts NewSegmentOpts) (Segment, error) {
 		return nil, fmt.Errorf("unsupported postings format: %v", metadata.PostingsFormat.String())
 	}
 
+	var (
+		digests     segmentDigests
+		lazyDigests *lazyDigests
+	)
+	if metadata.FileDigests {
+		if data.MinorVersion < fileDigestsMinorVersion {
+			return nil, fmt.Errorf("file digests are not supported by minor version: %d", data.MinorVersion)
+		}
+
+		var err error
+		digests, err = stripDigests(&data)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case opts.VerifyDigests:
+			if err := verifyDigests(data, digests); err != nil {
+				return nil, err
+			}
+		case opts.LazyVerifyDigests:
+			lazyDigests, err = newLazyDigests(&data, &digests)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	fieldsFST, err := vellum.Load(data.FSTFieldsData)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load fields fst: %v", err)
@@ -137,6 +186,7 @@ func NewSegment(data SegmentData, opts NewSegmentOpts) (Segment, error) {
 	docsDataReader := docs.NewDataReader(data.DocsData)
 
 	return &fsSegment{
+		id:              newSegmentID(),
 		fieldsFST:       fieldsFST,
 		docsDataReader:  docsDataReader,
 		docsIndexReader: docsIndexReader,
@@ -144,6 +194,11 @@ func NewSegment(data SegmentData, opts NewSegmentOpts) (Segment, error) {
 		data:           data,
 		opts:           opts,
 		numDocs:        metadata.NumDocs,
+		fieldPostings:  metadata.FieldPostings,
+		docFrequencies: metadata.DocFrequencies,
+		fileDigests:    metadata.FileDigests,
+		digests:        digests,
+		lazyDigests:    lazyDigests,
 		startInclusive: startInclusive,
 		endExclusive:   endExclusive,
 	}, nil
@@ -153,6 +208,7 @@ type fsSegment struct {
 	sync.RWMutex
 	closed bool
 
+	id              uint64
 	fieldsFST       *vellum.FST
 	docsDataReader  *docs.DataReader
 	docsIndexReader *docs.IndexReader
@@ -161,8 +217,16 @@ type fsSegment struct {
 	opts NewSegmentOpts
 
 	numDocs        int64
+	fieldPostings  bool
+	docFrequencies bool
+	fileDigests    bool
+	digests        segmentDigests
+	lazyDigests    *lazyDigests
 	startInclusive postings.ID
 	endExclusive   postings.ID
+
+	// Postings IDs of the documents which have been deleted, nil until the first deletion.
+	tombstones postings.MutableList
 }
 
 func (r *fsSegment) Size() int64 {
@@ -193,22 +257,55 @@ func (r *fsSegment) ContainsID(docID []byte) (bool, error) {
 	fstCloser := x.NewSafeCloser(termsFST)
 	defer fstCloser.Close()
 
-	_, exists, err = termsFST.Get(docID)
+	postingsOffset, exists, err := termsFST.Get(docID)
 	if err != nil {
 		return false, err
 	}
 
+	if exists && r.tombstones != nil {
+		// The segment only contains the ID if the document with the ID has not been deleted.
+		pl, err := r.retrievePostingsListWithRLock(postingsOffset)
+		if err != nil {
+			return false, err
+		}
+		exists, err = containsLiveIDs(pl, r.tombstones)
+		if err != nil {
+			return false, err
+		}
+	}
+
 	return exists, fstCloser.Close()
 }
 
+func (r *fsSegment) DeletePostings(pl postings.List) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed {
+		return errReaderClosed
+	}
+
+	if r.tombstones == nil {
+		r.tombstones = roaring.NewPostingsList()
+	}
+	return r.tombstones.AddIterator(pl.Iterator())
+}
+
 func (r *fsSegment) Reader() (index.Reader, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
 		return nil, errReaderClosed
 	}
+
+	// Readers use a snapshot of the tombstones so that deletions made after the reader
+	// is created are not visible to it.
+	var tombstones postings.List
+	if r.tombstones != nil {
+		tombstones = r.tombstones.Clone()
+	}
 	return &fsSegmentReader{
-		fsSegment: r,
+		fsSegment:  r,
+		tombstones: tombstones,
 	}, nil
 }
 
@@ -219,6 +316,9 @@ func (r *fsSegment) Close() error {
 		return errReaderClosed
 	}
 	r.closed = true
+	if r.opts.PostingsListCache != nil {
+		r.opts.PostingsListCache.purgeSegment(r.id)
+	}
 	var multiErr xerrors.MultiError
 	multiErr = multiErr.Add(r.fieldsFST.Close())
 	if r.data.Closer != nil {
@@ -241,6 +341,10 @@ func (r *fsSegment) Fields() ([][]byte, error) {
 	return r.allKeys(r.fieldsFST)
 }
 
+func (r *fsSegment) FieldNames() ([][]byte, error) {
+	return r.Fields()
+}
+
 func (r *fsSegment) Terms(field []byte) ([][]byte, error) {
 	r.RLock()
 	defer r.RUnlock()
@@ -278,7 +382,20 @@ func (r *fsSegment) MatchTerm(field []byte, term []byte) (postings.List, error)
 	if r.closed {
 		return nil, errReaderClosed
 	}
+	return r.matchTermWithRLock(field, term, r.tombstones)
+}
+
+func (r *fsSegment) matchTermWithRLock(field []byte, term []byte, tombstones postings.List) (postings.List, error) {
+	pl, err := r.cachedPostingsListWithRLock(field, term, termPatternType, func() (postings.List, error) {
+		return r.retrieveTermPostingsListWithRLock(field, term)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return excludeTombstones(pl, tombstones)
+}
 
+func (r *fsSegment) retrieveTermPostingsListWithRLock(field []byte, term []byte) (postings.List, error) {
 	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
 	if err != nil {
 		return nil, err
@@ -314,16 +431,76 @@ func (r *fsSegment) MatchTerm(field []byte, term []byte) (postings.List, error)
 	return pl, nil
 }
 
-func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Regexp) (postings.List, error) {
+func (r *fsSegment) DocFrequency(field []byte, term []byte) (int, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
-		return nil, errReaderClosed
+		return 0, errReaderClosed
 	}
+	return r.docFrequencyWithRLock(field, term)
+}
 
-	re, err := vregex.New(string(regexp))
+func (r *fsSegment) docFrequencyWithRLock(field []byte, term []byte) (int, error) {
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	postingsOffset, exists, err := termsFST.Get(term)
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	var freq int
+	if r.docFrequencies {
+		freq, err = r.retrieveDocFrequencyWithRLock(postingsOffset)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		// The document frequencies were not written so the frequency is only available
+		// without retrieving the postings list if the postings list has been cached.
+		cache := r.opts.PostingsListCache
+		if cache == nil {
+			return 0, index.ErrDocFrequencyUnavailable
+		}
+		pl, ok := cache.peek(r.id, field, term, termPatternType)
+		if !ok {
+			return 0, index.ErrDocFrequencyUnavailable
+		}
+		freq = pl.Len()
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return 0, err
+	}
+
+	return freq, nil
+}
+
+func (r *fsSegment) MatchTerms(field []byte, terms [][]byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.matchTermsWithRLock(field, terms, r.tombstones)
+}
+
+func (r *fsSegment) matchTermsWithRLock(field []byte, terms [][]byte, tombstones postings.List) (postings.List, error) {
+	if len(terms) == 0 {
+		return r.opts.PostingsListPool.Get(), nil
 	}
 
 	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
@@ -339,7 +516,7 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Re
 	var (
 		fstCloser     = x.NewSafeCloser(termsFST)
 		pl            = r.opts.PostingsListPool.Get()
-		iter, iterErr = termsFST.Search(re, minByteKey, maxByteKey)
+		iter, iterErr = termsFST.Iterator(terms[0], keySuccessor(terms[len(terms)-1]))
 		iterCloser    = x.NewSafeCloser(iter)
 	)
 	defer func() {
@@ -347,16 +524,26 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Re
 		fstCloser.Close()
 	}()
 
-	for {
+	// Walk the FST once, seeking forward to each of the terms in turn.
+	for _, term := range terms {
 		if iterErr == vellum.ErrIteratorDone {
 			break
 		}
-
 		if iterErr != nil {
 			return nil, iterErr
 		}
 
-		_, postingsOffset := iter.Current()
+		key, postingsOffset := iter.Current()
+		if bytes.Compare(key, term) < 0 {
+			if iterErr = iter.Seek(term); iterErr != nil {
+				continue
+			}
+			key, postingsOffset = iter.Current()
+		}
+		if !bytes.Equal(key, term) {
+			continue
+		}
+
 		nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
 		if err != nil {
 			return nil, err
@@ -364,8 +551,10 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Re
 		if err := pl.Union(nextPl); err != nil {
 			return nil, err
 		}
+	}
 
-		iterErr = iter.Next()
+	if iterErr != nil && iterErr != vellum.ErrIteratorDone {
+		return nil, iterErr
 	}
 
 	if err := iterCloser.Close(); err != nil {
@@ -376,19 +565,324 @@ func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Re
 		return nil, err
 	}
 
+	return excludeTombstones(pl, tombstones)
+}
+
+func (r *fsSegment) FieldTerms(field []byte) (index.TermsIterator, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.fieldTermsWithRLock(field)
+}
+
+func (r *fsSegment) fieldTermsWithRLock(field []byte) (index.TermsIterator, error) {
+	termsFST, _, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+	return newFSTermsIterator(r, termsFST), nil
+}
+
+func (r *fsSegment) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Regexp) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.matchRegexpWithRLock(field, regexp, compiled, r.tombstones)
+}
+
+func (r *fsSegment) matchRegexpWithRLock(field []byte, regexp []byte, compiled *regexp.Regexp, tombstones postings.List) (postings.List, error) {
+	pl, err := r.cachedPostingsListWithRLock(field, regexp, regexpPatternType, func() (postings.List, error) {
+		return r.retrieveRegexpPostingsListWithRLock(field, regexp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return excludeTombstones(pl, tombstones)
+}
+
+func (r *fsSegment) retrieveRegexpPostingsListWithRLock(field []byte, regexp []byte) (postings.List, error) {
+	re, err := vregex.New(string(regexp))
+	if err != nil {
+		return nil, err
+	}
+
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool.Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	pl, err := r.unionPostingsListsWithRLock(termsFST.Search(re, minByteKey, maxByteKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
 	return pl, nil
 }
 
+func (r *fsSegment) MatchPrefix(field []byte, prefix []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.matchPrefixWithRLock(field, prefix, r.tombstones)
+}
+
+func (r *fsSegment) matchPrefixWithRLock(field []byte, prefix []byte, tombstones postings.List) (postings.List, error) {
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool.Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	// The terms which begin with the prefix are exactly those in the range [prefix, end).
+	pl, err := r.unionPostingsListsWithRLock(termsFST.Iterator(prefix, prefixEnd(prefix)))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return excludeTombstones(pl, tombstones)
+}
+
+func (r *fsSegment) MatchTermRange(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.matchTermRangeWithRLock(field, min, max, minInclusive, maxInclusive, r.tombstones)
+}
+
+func (r *fsSegment) matchTermRangeWithRLock(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+	tombstones postings.List,
+) (postings.List, error) {
+	termsFST, exists, err := r.retrieveTermsFSTWithRLock(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool.Get(), nil
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	// Convert the bounds to the half-open range [start, end) used by the FST iterator.
+	start, end := minByteKey, []byte(nil)
+	if len(min) > 0 {
+		start = min
+		if !minInclusive {
+			start = keySuccessor(min)
+		}
+	}
+	if len(max) > 0 {
+		end = max
+		if maxInclusive {
+			end = keySuccessor(max)
+		}
+	}
+
+	if end != nil && bytes.Compare(start, end) >= 0 {
+		// i.e. the range is empty, so can early return an empty postings list
+		return r.opts.PostingsListPool.Get(), nil
+	}
+
+	pl, err := r.unionPostingsListsWithRLock(termsFST.Iterator(start, end))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return excludeTombstones(pl, tombstones)
+}
+
+func (r *fsSegment) MatchField(field []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errReaderClosed
+	}
+	return r.matchFieldWithRLock(field, r.tombstones)
+}
+
+func (r *fsSegment) matchFieldWithRLock(field []byte, tombstones postings.List) (postings.List, error) {
+	termsFSTOffset, exists, err := r.fieldsFST.Get(field)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		// i.e. we don't know anything about the field, so can early return an empty postings list
+		return r.opts.PostingsListPool.Get(), nil
+	}
+
+	if r.fieldPostings {
+		pl, err := r.retrieveFieldPostingsListWithRLock(termsFSTOffset)
+		if err != nil {
+			return nil, err
+		}
+		return excludeTombstones(pl, tombstones)
+	}
+
+	// The field's postings list was not written so take the union of its terms' instead.
+	termsFST, err := r.loadTermsFSTWithRLock(termsFSTOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	pl, err := r.unionPostingsListsWithRLock(termsFST.Iterator(minByteKey, nil))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fstCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return excludeTombstones(pl, tombstones)
+}
+
+// cachedPostingsListWithRLock returns the postings list of the provided pattern from the
+// postings list cache, retrieving it and adding it to the cache if it is not present.
+func (r *fsSegment) cachedPostingsListWithRLock(
+	field, pattern []byte,
+	t patternType,
+	retrieve func() (postings.List, error),
+) (postings.List, error) {
+	cache := r.opts.PostingsListCache
+	if cache == nil {
+		return retrieve()
+	}
+
+	if pl, ok := cache.get(r.id, field, pattern, t); ok {
+		return pl, nil
+	}
+
+	pl, err := retrieve()
+	if err != nil {
+		return nil, err
+	}
+	cache.put(r.id, field, pattern, t, pl)
+	return pl, nil
+}
+
+// unionPostingsListsWithRLock returns the union of the postings lists of the terms
+// returned by the provided iterator.
+func (r *fsSegment) unionPostingsListsWithRLock(iter *vellum.FSTIterator, iterErr error) (postings.List, error) {
+	var (
+		pl         = r.opts.PostingsListPool.Get()
+		iterCloser = x.NewSafeCloser(iter)
+	)
+	defer iterCloser.Close()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+
+		if iterErr != nil {
+			return nil, iterErr
+		}
+
+		_, postingsOffset := iter.Current()
+		nextPl, err := r.retrievePostingsListWithRLock(postingsOffset)
+		if err != nil {
+			return nil, err
+		}
+		if err := pl.Union(nextPl); err != nil {
+			return nil, err
+		}
+
+		iterErr = iter.Next()
+	}
+
+	if err := iterCloser.Close(); err != nil {
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+// keySuccessor returns the smallest key which is greater than the provided key.
+func keySuccessor(key []byte) []byte {
+	successor := make([]byte, len(key)+1)
+	copy(successor, key)
+	return successor
+}
+
+// prefixEnd returns the smallest key which is greater than every key beginning with the
+// provided prefix, or nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
 func (r *fsSegment) MatchAll() (postings.MutableList, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
 		return nil, errReaderClosed
 	}
+	return r.matchAllWithRLock(r.tombstones)
+}
 
+func (r *fsSegment) matchAllWithRLock(tombstones postings.List) (postings.MutableList, error) {
 	pl := r.opts.PostingsListPool.Get()
 	pl.AddRange(r.startInclusive, r.endExclusive)
 
+	if tombstones != nil {
+		if err := pl.Difference(tombstones); err != nil {
+			return nil, err
+		}
+	}
+
 	return pl, nil
 }
 
@@ -398,23 +892,47 @@ func (r *fsSegment) Doc(id postings.ID) (doc.Document, error) {
 	if r.closed {
 		return doc.Document{}, errReaderClosed
 	}
+	return r.docWithRLock(id, r.tombstones)
+}
+
+func (r *fsSegment) docWithRLock(id postings.ID, tombstones postings.List) (doc.Document, error) {
+	if tombstones != nil && tombstones.Contains(id) {
+		return doc.Document{}, index.ErrDocNotFound
+	}
 
 	offset, err := r.docsIndexReader.Read(id)
 	if err != nil {
 		return doc.Document{}, err
 	}
 
+	return r.readDocWithRLock(offset)
+}
+
+// readDocWithRLock reads the document at the provided offset in the documents data.
+func (r *fsSegment) readDocWithRLock(offset uint64) (doc.Document, error) {
+	if r.lazyDigests != nil {
+		if err := r.lazyDigests.docsData.verify(); err != nil {
+			return doc.Document{}, err
+		}
+	}
 	return r.docsDataReader.Read(offset)
 }
 
-func (r *fsSegment) Docs(pl postings.List) (doc.Iterator, error) {
+func (r *fsSegment) Docs(pl postings.List) (index.IDDocIterator, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
 		return nil, errReaderClosed
 	}
+	return r.docsWithRLock(pl, r.tombstones, r)
+}
 
-	return index.NewIDDocIterator(r, pl.Iterator()), nil
+func (r *fsSegment) docsWithRLock(
+	pl postings.List,
+	tombstones postings.List,
+	retriever index.DocRetriever,
+) (index.IDDocIterator, error) {
+	return index.NewIDDocIterator(retriever, excludeTombstonesIter(pl.Iterator(), tombstones)), nil
 }
 
 func (r *fsSegment) AllDocs() (index.IDDocIterator, error) {
@@ -423,12 +941,19 @@ func (r *fsSegment) AllDocs() (index.IDDocIterator, error) {
 	if r.closed {
 		return nil, errReaderClosed
 	}
+	return r.allDocsWithRLock(r.tombstones, r)
+}
+
+func (r *fsSegment) allDocsWithRLock(
+	tombstones postings.List,
+	retriever index.DocRetriever,
+) (index.IDDocIterator, error) {
 	pi := postings.NewRangeIterator(r.startInclusive, r.endExclusive)
-	return index.NewIDDocIterator(r, pi), nil
+	return index.NewIDDocIterator(retriever, excludeTombstonesIter(pi, tombstones)), nil
 }
 
 func (r *fsSegment) retrievePostingsListWithRLock(postingsOffset uint64) (postings.List, error) {
-	postingsBytes, err := r.retrieveBytesWithRLock(r.data.PostingsData, postingsOffset)
+	postingsBytes, err := r.retrievePostingsBytesWithRLock(postingsOffset)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve postings data: %v", err)
 	}
@@ -472,17 +997,98 @@ func (r *fsSegment) retrieveTermsFSTWithRLock(field []byte) (*vellum.FST, bool,
 		return nil, false, nil
 	}
 
-	termsFSTBytes, err := r.retrieveBytesWithRLock(r.data.FSTTermsData, termsFSTOffset)
+	termsFST, err := r.loadTermsFSTWithRLock(termsFSTOffset)
 	if err != nil {
-		return nil, false, fmt.Errorf("error while decoding terms fst: %v", err)
+		return nil, false, err
+	}
+
+	return termsFST, true, nil
+}
+
+// retrieveFieldPostingsListWithRLock returns the postings list of the field whose terms
+// FST ends at the provided offset. The offset of the field's postings list is written
+// immediately before the terms FST.
+func (r *fsSegment) retrieveFieldPostingsListWithRLock(termsFSTOffset uint64) (postings.List, error) {
+	termsFSTBytes, err := r.retrieveFSTTermsBytesWithRLock(termsFSTOffset)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding terms fst: %v", err)
+	}
+
+	// Skip over the terms FST along with its size and magic number.
+	const sizeofUint64 = 8
+	termsFSTStart := termsFSTOffset - uint64(len(termsFSTBytes)) - 2*sizeofUint64
+	postingsOffsetBytes, err := r.retrieveFSTTermsBytesWithRLock(termsFSTStart)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding field postings offset: %v", err)
+	}
+
+	postingsOffset, err := encoding.NewDecoder(postingsOffsetBytes).Uint64()
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding field postings offset: %v", err)
+	}
+
+	return r.retrievePostingsListWithRLock(postingsOffset)
+}
+
+// retrieveDocFrequencyWithRLock returns the number of documents in the postings list
+// which ends at the provided offset. The document frequency of a term is written
+// immediately before its postings list.
+func (r *fsSegment) retrieveDocFrequencyWithRLock(postingsOffset uint64) (int, error) {
+	postingsBytes, err := r.retrievePostingsBytesWithRLock(postingsOffset)
+	if err != nil {
+		return 0, fmt.Errorf("unable to retrieve postings data: %v", err)
+	}
+
+	// Skip over the postings list along with its size and magic number.
+	const sizeofUint64 = 8
+	postingsStart := postingsOffset - uint64(len(postingsBytes)) - 2*sizeofUint64
+	freqBytes, err := r.retrievePostingsBytesWithRLock(postingsStart)
+	if err != nil {
+		return 0, fmt.Errorf("error while decoding document frequency: %v", err)
+	}
+
+	freq, err := encoding.NewDecoder(freqBytes).Uint64()
+	if err != nil {
+		return 0, fmt.Errorf("error while decoding document frequency: %v", err)
+	}
+
+	return int(freq), nil
+}
+
+func (r *fsSegment) loadTermsFSTWithRLock(termsFSTOffset uint64) (*vellum.FST, error) {
+	termsFSTBytes, err := r.retrieveFSTTermsBytesWithRLock(termsFSTOffset)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding terms fst: %v", err)
 	}
 
 	termsFST, err := vellum.Load(termsFSTBytes)
 	if err != nil {
-		return nil, false, fmt.Errorf("error while loading terms fst: %v", err)
+		return nil, fmt.Errorf("error while loading terms fst: %v", err)
 	}
 
-	return termsFST, true, nil
+	return termsFST, nil
+}
+
+// retrievePostingsBytesWithRLock returns the record which ends at the provided offset in
+// the postings data.
+func (r *fsSegment) retrievePostingsBytesWithRLock(offset uint64) ([]byte, error) {
+	if r.lazyDigests != nil {
+		if err := r.lazyDigests.postingsData.verify(); err != nil {
+			return nil, err
+		}
+	}
+	return r.retrieveBytesWithRLock(r.data.PostingsData, offset)
+}
+
+// retrieveFSTTermsBytesWithRLock returns the record which ends at the provided offset in
+// the FST terms data.
+func (r *fsSegment) retrieveFSTTermsBytesWithRLock(offset uint64) ([]byte, error) {
+	if r.lazyDigests != nil {
+		if err := r.lazyDigests.fstTermsData.verify(); err != nil {
+			return nil, err
+		}
+	}
+	return r.retrieveBytesWithRLock(r.data.FSTTermsData, offset)
 }
 
 // retrieveBytesWithRLock assumes the base []byte slice is a collection of (payload, size, magicNumber) triples,
@@ -538,7 +1144,8 @@ type fsSegmentReader struct {
 	sync.RWMutex
 	closed bool
 
-	fsSegment *fsSegment
+	fsSegment  *fsSegment
+	tombstones postings.List
 }
 
 var _ index.Reader = &fsSegmentReader{}
@@ -549,7 +1156,73 @@ func (sr *fsSegmentReader) MatchTerm(field []byte, term []byte) (postings.List,
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.MatchTerm(field, term)
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchTermWithRLock(field, term, sr.tombstones)
+}
+
+func (sr *fsSegmentReader) DocFrequency(field []byte, term []byte) (int, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return 0, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return 0, errReaderClosed
+	}
+	return sr.fsSegment.docFrequencyWithRLock(field, term)
+}
+
+func (sr *fsSegmentReader) MatchTerms(field []byte, terms [][]byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchTermsWithRLock(field, terms, sr.tombstones)
+}
+
+func (sr *fsSegmentReader) FieldNames() ([][]byte, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.allKeys(sr.fsSegment.fieldsFST)
+}
+
+func (sr *fsSegmentReader) FieldTerms(field []byte) (index.TermsIterator, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.fieldTermsWithRLock(field)
 }
 
 func (sr *fsSegmentReader) MatchRegexp(field []byte, regexp []byte, compiled *regexp.Regexp) (postings.List, error) {
@@ -558,7 +1231,61 @@ func (sr *fsSegmentReader) MatchRegexp(field []byte, regexp []byte, compiled *re
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.MatchRegexp(field, regexp, compiled)
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchRegexpWithRLock(field, regexp, compiled, sr.tombstones)
+}
+
+func (sr *fsSegmentReader) MatchPrefix(field []byte, prefix []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchPrefixWithRLock(field, prefix, sr.tombstones)
+}
+
+func (sr *fsSegmentReader) MatchTermRange(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchTermRangeWithRLock(field, min, max, minInclusive, maxInclusive, sr.tombstones)
+}
+
+func (sr *fsSegmentReader) MatchField(field []byte) (postings.List, error) {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.closed {
+		return nil, errReaderClosed
+	}
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchFieldWithRLock(field, sr.tombstones)
 }
 
 func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
@@ -567,7 +1294,13 @@ func (sr *fsSegmentReader) MatchAll() (postings.MutableList, error) {
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.MatchAll()
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.matchAllWithRLock(sr.tombstones)
 }
 
 func (sr *fsSegmentReader) Doc(id postings.ID) (doc.Document, error) {
@@ -576,16 +1309,28 @@ func (sr *fsSegmentReader) Doc(id postings.ID) (doc.Document, error) {
 	if sr.closed {
 		return doc.Document{}, errReaderClosed
 	}
-	return sr.fsSegment.Doc(id)
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return doc.Document{}, errReaderClosed
+	}
+	return sr.fsSegment.docWithRLock(id, sr.tombstones)
 }
 
-func (sr *fsSegmentReader) Docs(pl postings.List) (doc.Iterator, error) {
+func (sr *fsSegmentReader) Docs(pl postings.List) (index.IDDocIterator, error) {
 	sr.RLock()
 	defer sr.RUnlock()
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.Docs(pl)
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.docsWithRLock(pl, sr.tombstones, sr)
 }
 
 func (sr *fsSegmentReader) AllDocs() (index.IDDocIterator, error) {
@@ -594,7 +1339,13 @@ func (sr *fsSegmentReader) AllDocs() (index.IDDocIterator, error) {
 	if sr.closed {
 		return nil, errReaderClosed
 	}
-	return sr.fsSegment.AllDocs()
+
+	sr.fsSegment.RLock()
+	defer sr.fsSegment.RUnlock()
+	if sr.fsSegment.closed {
+		return nil, errReaderClosed
+	}
+	return sr.fsSegment.allDocsWithRLock(sr.tombstones, sr)
 }
 
 func (sr *fsSegmentReader) Close() error {
@@ -617,3 +1368,36 @@ func (r *fsSegment) copyBytes(b []byte) []byte {
 	copy(copied, b)
 	return copied
 }
+
+// excludeTombstones returns the provided postings list without any deleted documents.
+func excludeTombstones(pl postings.List, tombstones postings.List) (postings.List, error) {
+	if tombstones == nil || pl.IsEmpty() {
+		return pl, nil
+	}
+
+	clone := pl.Clone()
+	if err := clone.Difference(tombstones); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// excludeTombstonesIter returns an iterator over the IDs in the provided iterator
+// without any deleted documents.
+func excludeTombstonesIter(iter postings.Iterator, tombstones postings.List) postings.Iterator {
+	if tombstones == nil {
+		return iter
+	}
+	return postings.NewDifferenceIterator(iter, tombstones)
+}
+
+// containsLiveIDs returns whether the provided postings list contains any IDs which
+// have not been deleted.
+func containsLiveIDs(pl postings.List, tombstones postings.List) (bool, error) {
+	iter := postings.NewDifferenceIterator(pl.Iterator(), tombstones)
+	contains := iter.Next()
+	if err := iter.Close(); err != nil {
+		return false, err
+	}
+	return contains, nil
+}
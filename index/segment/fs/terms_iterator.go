@@ -0,0 +1,128 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"errors"
+	"io"
+
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/x"
+
+	"github.com/couchbase/vellum"
+)
+
+var (
+	errTermsIteratorClosed = errors.New("terms iterator is closed")
+)
+
+// fsTermsIterator is an iterator over the terms FST of a field. The segment's read lock
+// is acquired on each call to Next so the segment can't be closed while the iterator is
+// reading from it.
+type fsTermsIterator struct {
+	segment    *fsSegment
+	fstCloser  io.Closer
+	iter       *vellum.FSTIterator
+	iterCloser io.Closer
+	iterErr    error
+	started    bool
+
+	currTerm []byte
+	currPl   postings.List
+	err      error
+	closed   bool
+}
+
+// newFSTermsIterator returns a new iterator over the provided terms FST. The FST may be
+// nil in which case the iterator is empty.
+func newFSTermsIterator(segment *fsSegment, termsFST *vellum.FST) *fsTermsIterator {
+	it := &fsTermsIterator{
+		segment: segment,
+		iterErr: vellum.ErrIteratorDone,
+	}
+	if termsFST != nil {
+		it.fstCloser = x.NewSafeCloser(termsFST)
+		it.iter, it.iterErr = termsFST.Iterator(minByteKey, nil)
+		it.iterCloser = x.NewSafeCloser(it.iter)
+	}
+	return it
+}
+
+func (it *fsTermsIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+
+	it.segment.RLock()
+	defer it.segment.RUnlock()
+	if it.segment.closed {
+		it.err = errReaderClosed
+		return false
+	}
+
+	if it.started && it.iterErr == nil {
+		it.iterErr = it.iter.Next()
+	}
+	it.started = true
+
+	if it.iterErr == vellum.ErrIteratorDone {
+		it.currTerm, it.currPl = nil, nil
+		return false
+	}
+	if it.iterErr != nil {
+		it.err = it.iterErr
+		return false
+	}
+
+	term, postingsOffset := it.iter.Current()
+	pl, err := it.segment.retrievePostingsListWithRLock(postingsOffset)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.currTerm, it.currPl = term, pl
+	return true
+}
+
+func (it *fsTermsIterator) Current() ([]byte, postings.List) {
+	return it.currTerm, it.currPl
+}
+
+func (it *fsTermsIterator) Err() error {
+	return it.err
+}
+
+func (it *fsTermsIterator) Close() error {
+	if it.closed {
+		return errTermsIteratorClosed
+	}
+	it.closed = true
+	it.currTerm, it.currPl = nil, nil
+
+	if it.fstCloser == nil {
+		return nil
+	}
+	if err := it.iterCloser.Close(); err != nil {
+		return err
+	}
+	return it.fstCloser.Close()
+}
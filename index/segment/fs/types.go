@@ -34,21 +34,68 @@ const (
 	MajorVersion = 1
 
 	// MinorVersion is the current MinorVersion.
-	MinorVersion = 0
+	MinorVersion = 2
+
+	// fileDigestsMinorVersion is the MinorVersion of segments whose files end with digest
+	// trailers. Segments written without them keep the previous MinorVersion so that they
+	// remain readable by readers which are unaware of the digests.
+	fileDigestsMinorVersion = 2
 )
 
+// WriterOpts represent the collection of knobs used by the writers.
+type WriterOpts struct {
+	// FieldPostingsLists sets whether a postings list of all the documents containing each
+	// field is written alongside the postings lists of its terms so that matching a field
+	// only requires retrieving a single postings list.
+	FieldPostingsLists bool
+
+	// DocFrequencies sets whether the number of documents containing each term is written
+	// alongside the term's postings list so that the cardinality of a term can be
+	// estimated without retrieving its postings list.
+	DocFrequencies bool
+
+	// FileDigests sets whether each file is written with a trailer containing the digest
+	// of its contents so that corruption can be detected. Segments written with digests
+	// have a newer MinorVersion and can only be read by readers which are aware of them.
+	FileDigests bool
+}
+
 // Segment represents a FST segment.
 type Segment interface {
 	sgmt.Segment
 	index.Readable
+
+	// Verify checks the integrity of the segment by verifying the digests of its files, if
+	// they were written, and reading each of its FSTs, postings lists and documents.
+	Verify() error
 }
 
 // Writer writes out a FST segment from the provided elements.
 type Writer interface {
+	FilesWriter
+
 	// Reset sets the Writer to persist the provide segment.
 	// NB(prateek): the provided segment must be a Sealed Mutable segment.
-	Reset(s sgmt.MutableSegment) error
+	//
+	// An immutable segment may also be provided since it cannot be modified while it
+	// is written. Deleted documents are dropped from the written segment.
+	Reset(s sgmt.Segment) error
+}
+
+// MergeWriter writes out a FST segment containing the documents of several FST segments.
+// The segments are merged directly from their FSTs and postings lists so the documents
+// are not re-indexed.
+type MergeWriter interface {
+	FilesWriter
+
+	// Reset sets the MergeWriter to persist the merge of the provided segments, which
+	// must have been created by NewSegment. Deleted documents are dropped and if several
+	// of the segments contain a document with the same ID only the first is kept.
+	Reset(segs []Segment) error
+}
 
+// FilesWriter writes out the files which make up a FST segment.
+type FilesWriter interface {
 	// MajorVersion is the major version for the writer.
 	MajorVersion() int
 
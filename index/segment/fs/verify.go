@@ -0,0 +1,178 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/x"
+
+	"github.com/couchbase/vellum"
+)
+
+func (r *fsSegment) Verify() error {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return errReaderClosed
+	}
+
+	if r.fileDigests {
+		if err := verifyDigests(r.data, r.digests); err != nil {
+			return err
+		}
+	}
+
+	if err := r.verifyFieldsWithRLock(); err != nil {
+		return err
+	}
+	return r.verifyDocumentsWithRLock()
+}
+
+// verifyFieldsWithRLock reads the terms FST of each field along with the postings list of
+// each of its terms.
+func (r *fsSegment) verifyFieldsWithRLock() error {
+	return forEachFSTEntry(r.fieldsFST, func(field []byte, termsFSTOffset uint64) error {
+		if err := r.verifyFieldWithRLock(termsFSTOffset); err != nil {
+			return fmt.Errorf("unable to verify field %s: %v", field, err)
+		}
+		return nil
+	})
+}
+
+func (r *fsSegment) verifyFieldWithRLock(termsFSTOffset uint64) error {
+	if r.fieldPostings {
+		pl, err := r.retrieveFieldPostingsListWithRLock(termsFSTOffset)
+		if err != nil {
+			return err
+		}
+		if err := r.verifyPostingsListWithRLock(pl); err != nil {
+			return err
+		}
+	}
+
+	termsFST, err := r.loadTermsFSTWithRLock(termsFSTOffset)
+	if err != nil {
+		return err
+	}
+
+	fstCloser := x.NewSafeCloser(termsFST)
+	defer fstCloser.Close()
+
+	err = forEachFSTEntry(termsFST, func(term []byte, postingsOffset uint64) error {
+		if err := r.verifyTermWithRLock(postingsOffset); err != nil {
+			return fmt.Errorf("unable to verify term %s: %v", term, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return fstCloser.Close()
+}
+
+func (r *fsSegment) verifyTermWithRLock(postingsOffset uint64) error {
+	pl, err := r.retrievePostingsListWithRLock(postingsOffset)
+	if err != nil {
+		return err
+	}
+	if err := r.verifyPostingsListWithRLock(pl); err != nil {
+		return err
+	}
+
+	if !r.docFrequencies {
+		return nil
+	}
+
+	freq, err := r.retrieveDocFrequencyWithRLock(postingsOffset)
+	if err != nil {
+		return err
+	}
+	if freq != pl.Len() {
+		return fmt.Errorf("document frequency %d does not match postings list length %d", freq, pl.Len())
+	}
+	return nil
+}
+
+// verifyPostingsListWithRLock verifies the provided postings list only contains IDs of
+// documents in the segment.
+func (r *fsSegment) verifyPostingsListWithRLock(pl postings.List) error {
+	if pl.IsEmpty() {
+		return nil
+	}
+
+	min, err := pl.Min()
+	if err != nil {
+		return err
+	}
+	max, err := pl.Max()
+	if err != nil {
+		return err
+	}
+
+	if min < r.startInclusive || max >= r.endExclusive {
+		return fmt.Errorf("postings list contains IDs in [%d, %d] outside of the segment's range [%d, %d)",
+			min, max, r.startInclusive, r.endExclusive)
+	}
+	return nil
+}
+
+// verifyDocumentsWithRLock reads the offset of each document from the documents index and
+// then the document itself from the documents data.
+func (r *fsSegment) verifyDocumentsWithRLock() error {
+	for id := r.startInclusive; id < r.endExclusive; id++ {
+		offset, err := r.docsIndexReader.Read(id)
+		if err != nil {
+			return fmt.Errorf("unable to read offset of document %d: %v", id, err)
+		}
+		if _, err := r.docsDataReader.Read(offset); err != nil {
+			return fmt.Errorf("unable to read document %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// forEachFSTEntry calls the provided function with each of the keys of an FST in order
+// along with their values.
+func forEachFSTEntry(fst *vellum.FST, fn func(key []byte, value uint64) error) error {
+	iter, iterErr := fst.Iterator(minByteKey, nil)
+	iterCloser := x.NewSafeCloser(iter)
+	defer iterCloser.Close()
+
+	for {
+		if iterErr == vellum.ErrIteratorDone {
+			break
+		}
+		if iterErr != nil {
+			return iterErr
+		}
+
+		key, value := iter.Current()
+		if err := fn(key, value); err != nil {
+			return err
+		}
+		iterErr = iter.Next()
+	}
+
+	return iterCloser.Close()
+}
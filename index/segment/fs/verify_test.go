@@ -0,0 +1,216 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package fs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/m3db/m3ninx/generated/proto/fswriter"
+	sgmt "github.com/m3db/m3ninx/index/segment"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentVerify(t *testing.T) {
+	for _, test := range testDocuments {
+		for _, opts := range []WriterOpts{
+			{},
+			{FieldPostingsLists: true, DocFrequencies: true},
+			{FileDigests: true},
+		} {
+			name := fmt.Sprintf("%s, field postings lists: %v, doc frequencies: %v, file digests: %v",
+				test.name, opts.FieldPostingsLists, opts.DocFrequencies, opts.FileDigests)
+			t.Run(name, func(t *testing.T) {
+				memSeg := newTestMemSegment(t)
+				for _, d := range test.docs {
+					_, err := memSeg.Insert(d)
+					require.NoError(t, err)
+				}
+				fstSeg := newFSTSegmentWithOpts(t, memSeg, opts)
+
+				mw := NewMergeWriter(opts)
+				require.NoError(t, mw.Reset([]Segment{fstSeg.(Segment)}))
+				mergedSeg := newSegmentFromWriter(t, mw)
+
+				for _, seg := range []sgmt.Segment{fstSeg, mergedSeg} {
+					require.NoError(t, seg.(Segment).Verify())
+					require.NoError(t, seg.Close())
+					require.Equal(t, errReaderClosed, seg.(Segment).Verify())
+				}
+			})
+		}
+	}
+}
+
+func TestSegmentVerifyDigestMismatch(t *testing.T) {
+	data := newTestSegmentData(t)
+
+	// Flip a bit in the documents data.
+	data.DocsData[0] ^= 1
+
+	opts := NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+		VerifyDigests:    true,
+	}
+	_, err := NewSegment(data, opts)
+	require.Error(t, err)
+
+	// Otherwise the digests are only verified by Verify.
+	opts.VerifyDigests = false
+	seg, err := NewSegment(data, opts)
+	require.NoError(t, err)
+	require.Error(t, seg.Verify())
+	require.NoError(t, seg.Close())
+}
+
+func TestWriterFileDigestsOptIn(t *testing.T) {
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err := memSeg.Seal()
+	require.NoError(t, err)
+
+	w := NewWriter()
+	require.NoError(t, w.Reset(memSeg))
+	withoutDigests := newSegmentDataFromWriter(t, w)
+
+	w = NewWriterWithOpts(WriterOpts{FileDigests: true})
+	require.NoError(t, w.Reset(memSeg))
+	withDigests := newSegmentDataFromWriter(t, w)
+
+	// Segments are only written with digests, and the newer minor version, if the writer
+	// is asked for them.
+	var metadata fswriter.Metadata
+	require.NoError(t, metadata.Unmarshal(withoutDigests.Metadata))
+	require.False(t, metadata.FileDigests)
+	require.Equal(t, fileDigestsMinorVersion-1, withoutDigests.MinorVersion)
+	require.NoError(t, metadata.Unmarshal(withDigests.Metadata))
+	require.True(t, metadata.FileDigests)
+	require.Equal(t, fileDigestsMinorVersion, withDigests.MinorVersion)
+
+	// Each file should end with a trailer containing the digest of its contents.
+	digests, err := stripDigests(&withDigests)
+	require.NoError(t, err)
+	require.NoError(t, verifyDigests(withDigests, digests))
+}
+
+func TestNewSegmentDigestsUnsupportedMinorVersion(t *testing.T) {
+	data := newTestSegmentData(t)
+	data.MinorVersion = fileDigestsMinorVersion - 1
+
+	_, err := NewSegment(data, NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+	})
+	require.Error(t, err)
+
+	data.MinorVersion = MinorVersion + 1
+	_, err = NewSegment(data, NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+	})
+	require.Equal(t, errUnsupportedMinorVersion, err)
+}
+
+func TestSegmentLazyVerifyDigests(t *testing.T) {
+	opts := NewSegmentOpts{
+		PostingsListPool:  postings.NewPool(nil, roaring.NewPostingsList),
+		LazyVerifyDigests: true,
+	}
+
+	// The fields FST is read when the segment is created so its digest is verified then.
+	data := newTestSegmentData(t)
+	data.FSTFieldsData[0] ^= 1
+	_, err := NewSegment(data, opts)
+	require.Error(t, err)
+
+	// Otherwise the digest of a file is only verified when it is first accessed.
+	data = newTestSegmentData(t)
+	data.PostingsData[0] ^= 1
+	seg, err := NewSegment(data, opts)
+	require.NoError(t, err)
+
+	r, err := seg.Reader()
+	require.NoError(t, err)
+	iter, err := r.AllDocs()
+	require.NoError(t, err)
+	docs, err := collectDocs(iter)
+	require.NoError(t, err)
+	require.Len(t, docs, len(fewTestDocuments))
+
+	_, err = r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.Error(t, err)
+	_, err = r.MatchTerm([]byte("fruit"), []byte("banana"))
+	require.Error(t, err)
+
+	require.NoError(t, r.Close())
+	require.NoError(t, seg.Close())
+}
+
+func TestNewSegmentDigestMissing(t *testing.T) {
+	data := newTestSegmentData(t)
+	data.FSTFieldsData = data.FSTFieldsData[:digestLen-1]
+
+	_, err := NewSegment(data, NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+	})
+	require.Error(t, err)
+}
+
+func TestSegmentVerifyCorruptPostings(t *testing.T) {
+	data := newTestSegmentData(t)
+
+	// Remove the digests so that only the structure of the files is verified.
+	_, err := stripDigests(&data)
+	require.NoError(t, err)
+	var metadata fswriter.Metadata
+	require.NoError(t, metadata.Unmarshal(data.Metadata))
+	metadata.FileDigests = false
+	data.Metadata, err = metadata.Marshal()
+	require.NoError(t, err)
+
+	// Corrupt the magic number of the last postings list.
+	data.PostingsData[len(data.PostingsData)-1] ^= 1
+
+	seg, err := NewSegment(data, NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+	})
+	require.NoError(t, err)
+	require.Error(t, seg.Verify())
+	require.NoError(t, seg.Close())
+}
+
+func newTestSegmentData(t *testing.T) SegmentData {
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err := memSeg.Seal()
+	require.NoError(t, err)
+
+	w := NewWriterWithOpts(WriterOpts{FileDigests: true})
+	require.NoError(t, w.Reset(memSeg))
+	return newSegmentDataFromWriter(t, w)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/m3db/m3ninx/index/segment/fs/encoding/docs"
 	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/postings/pilosa"
+	"github.com/m3db/m3ninx/postings/roaring"
 	"github.com/m3db/m3ninx/x"
 )
 
@@ -44,11 +45,13 @@ var (
 	defaultInitialDocOffsetsSize         = 1024
 	defaultInitialIntEncoderSize         = 128
 
-	errUnableToFindPostingsOffset = errors.New("internal error: unable to find postings offset")
-	errUnableToFindFSTTermsOffset = errors.New("internal error: unable to find fst terms offset")
+	errUnableToFindPostingsOffset      = errors.New("internal error: unable to find postings offset")
+	errUnableToFindFSTTermsOffset      = errors.New("internal error: unable to find fst terms offset")
+	errUnableToFindFieldPostingsOffset = errors.New("internal error: unable to find field postings offset")
 )
 
 type writer struct {
+	opts      WriterOpts
 	seg       sgmt.Segment
 	segReader index.Reader
 
@@ -58,26 +61,39 @@ type writer struct {
 	docDataWriter   *docs.DataWriter
 	docIndexWriter  *docs.IndexWriter
 
-	metadata            []byte
-	docsDataFileWritten bool
-	postingsFileWritten bool
-	fstTermsFileWritten bool
-	postingsOffsets     *postingsOffsetsMap
-	fstTermsOffsets     *fstTermsOffsetsMap
-	docOffsets          []docOffset
+	metadata             []byte
+	docsDataFileWritten  bool
+	postingsFileWritten  bool
+	fstTermsFileWritten  bool
+	postingsOffsets      *postingsOffsetsMap
+	fstTermsOffsets      *fstTermsOffsetsMap
+	fieldPostingsOffsets *fstTermsOffsetsMap
+	docOffsets           []docOffset
+
+	// Postings IDs of the documents in the segment which have not been deleted, only
+	// set if the IDs are not contiguous in which case they are remapped so the IDs
+	// written are.
+	liveIDs []postings.ID
 }
 
-// NewWriter returns a new writer.
+// NewWriter returns a new writer with the default options.
 func NewWriter() Writer {
+	return NewWriterWithOpts(WriterOpts{})
+}
+
+// NewWriterWithOpts returns a new writer with the provided options.
+func NewWriterWithOpts(opts WriterOpts) Writer {
 	return &writer{
-		intEncoder:      encoding.NewEncoder(defaultInitialIntEncoderSize),
-		postingsEncoder: pilosa.NewEncoder(),
-		fstWriter:       newFSTWriter(),
-		docDataWriter:   docs.NewDataWriter(nil),
-		docIndexWriter:  docs.NewIndexWriter(nil),
-		postingsOffsets: newPostingsOffsetsMap(defaultInitialPostingsOffsetsMapSize),
-		fstTermsOffsets: newFSTTermsOffsetsMap(defaultInitialFSTTermsOffsetsMapSize),
-		docOffsets:      make([]docOffset, 0, defaultInitialDocOffsetsSize),
+		opts:                 opts,
+		intEncoder:           encoding.NewEncoder(defaultInitialIntEncoderSize),
+		postingsEncoder:      pilosa.NewEncoder(),
+		fstWriter:            newFSTWriter(),
+		docDataWriter:        docs.NewDataWriter(nil),
+		docIndexWriter:       docs.NewIndexWriter(nil),
+		postingsOffsets:      newPostingsOffsetsMap(defaultInitialPostingsOffsetsMapSize),
+		fstTermsOffsets:      newFSTTermsOffsetsMap(defaultInitialFSTTermsOffsetsMapSize),
+		fieldPostingsOffsets: newFSTTermsOffsetsMap(defaultInitialFSTTermsOffsetsMapSize),
+		docOffsets:           make([]docOffset, 0, defaultInitialDocOffsetsSize),
 	}
 }
 
@@ -97,26 +113,45 @@ func (w *writer) clear() {
 	w.fstTermsFileWritten = false
 	w.postingsOffsets.Reset()
 	w.fstTermsOffsets.Reset()
+	w.fieldPostingsOffsets.Reset()
 	w.docOffsets = w.docOffsets[:0]
+	w.liveIDs = w.liveIDs[:0]
 }
 
-func (w *writer) Reset(s sgmt.MutableSegment) error {
+func (w *writer) Reset(s sgmt.Segment) error {
 	w.clear()
 
 	if s == nil {
 		return nil
 	}
 
-	numDocs := s.Size()
-	metadata := defaultV1Metadata()
-	metadata.NumDocs = numDocs
-	metadataBytes, err := metadata.Marshal()
+	reader, err := s.Reader()
 	if err != nil {
 		return err
 	}
 
-	reader, err := s.Reader()
+	// Deleted documents are excluded from the reader so they are dropped from the
+	// written segment.
+	live, err := reader.MatchAll()
+	if err != nil {
+		reader.Close()
+		return err
+	}
+
+	numDocs := int64(live.Len())
+	if err := w.computeLiveIDs(live); err != nil {
+		reader.Close()
+		return err
+	}
+
+	metadata := defaultV1Metadata()
+	metadata.NumDocs = numDocs
+	metadata.FieldPostings = w.opts.FieldPostingsLists
+	metadata.DocFrequencies = w.opts.DocFrequencies
+	metadata.FileDigests = w.opts.FileDigests
+	metadataBytes, err := metadata.Marshal()
 	if err != nil {
+		reader.Close()
 		return err
 	}
 
@@ -126,12 +161,68 @@ func (w *writer) Reset(s sgmt.MutableSegment) error {
 	return nil
 }
 
+// computeLiveIDs records the IDs of the documents which have not been deleted if there
+// are gaps between them since the IDs in the written segment must be contiguous.
+func (w *writer) computeLiveIDs(live postings.List) error {
+	min, err := live.Min()
+	if err != nil {
+		// The postings list is empty.
+		return nil
+	}
+	max, err := live.Max()
+	if err != nil {
+		return err
+	}
+	if int(max-min)+1 == live.Len() {
+		return nil
+	}
+
+	iter := live.Iterator()
+	for iter.Next() {
+		w.liveIDs = append(w.liveIDs, iter.Current())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return iter.Close()
+}
+
+// remapID returns the ID the document with the provided ID is written with.
+func (w *writer) remapID(id postings.ID) postings.ID {
+	if len(w.liveIDs) == 0 {
+		return id
+	}
+	idx := sort.Search(len(w.liveIDs), func(i int) bool {
+		return w.liveIDs[i] >= id
+	})
+	return w.liveIDs[0] + postings.ID(idx)
+}
+
+// remapPostingsList returns the provided postings list with each of its IDs remapped
+// to the ID the document is written with.
+func (w *writer) remapPostingsList(pl postings.List) (postings.List, error) {
+	if len(w.liveIDs) == 0 {
+		return pl, nil
+	}
+
+	remapped := roaring.NewPostingsList()
+	iter := pl.Iterator()
+	for iter.Next() {
+		remapped.Insert(w.remapID(iter.Current()))
+	}
+	if err := iter.Err(); err != nil {
+		iter.Close()
+		return nil, err
+	}
+	return remapped, iter.Close()
+}
+
 func (w *writer) MajorVersion() int {
 	return MajorVersion
 }
 
 func (w *writer) MinorVersion() int {
-	return MinorVersion
+	return writerMinorVersion(w.opts)
 }
 
 func (w *writer) Metadata() []byte {
@@ -139,6 +230,10 @@ func (w *writer) Metadata() []byte {
 }
 
 func (w *writer) WriteDocumentsData(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeDocumentsData)
+}
+
+func (w *writer) writeDocumentsData(iow io.Writer) error {
 	w.docDataWriter.Reset(iow)
 
 	iter, err := w.segReader.AllDocs()
@@ -158,7 +253,7 @@ func (w *writer) WriteDocumentsData(iow io.Writer) error {
 		if err != nil {
 			return err
 		}
-		w.docOffsets = append(w.docOffsets, docOffset{ID: id, offset: currOffset})
+		w.docOffsets = append(w.docOffsets, docOffset{ID: w.remapID(id), offset: currOffset})
 		currOffset += uint64(n)
 	}
 
@@ -167,6 +262,10 @@ func (w *writer) WriteDocumentsData(iow io.Writer) error {
 }
 
 func (w *writer) WriteDocumentsIndex(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeDocumentsIndex)
+}
+
+func (w *writer) writeDocumentsIndex(iow io.Writer) error {
 	if !w.docsDataFileWritten {
 		return fmt.Errorf("documents data file has to be written before documents index file")
 	}
@@ -183,6 +282,10 @@ func (w *writer) WriteDocumentsIndex(iow io.Writer) error {
 }
 
 func (w *writer) WritePostingsOffsets(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writePostingsOffsets)
+}
+
+func (w *writer) writePostingsOffsets(iow io.Writer) error {
 	currentOffset := uint64(0)
 
 	// retrieve known fields
@@ -199,6 +302,13 @@ func (w *writer) WritePostingsOffsets(iow io.Writer) error {
 			return err
 		}
 
+		// the union of the postings lists of the field's terms, only tracked if the
+		// field's postings list is written
+		var fieldPl postings.MutableList
+		if w.opts.FieldPostingsLists {
+			fieldPl = roaring.NewPostingsList()
+		}
+
 		// for each term corresponding to the current field
 		for _, t := range terms {
 			// retrieve the postings list for this (field, term) combination
@@ -207,14 +317,18 @@ func (w *writer) WritePostingsOffsets(iow io.Writer) error {
 				return err
 			}
 
-			// serialize the postings list
-			w.postingsEncoder.Reset()
-			postingsBytes, err := w.postingsEncoder.Encode(pl)
+			// skip terms which only appear in deleted documents
+			if pl.IsEmpty() {
+				continue
+			}
+
+			pl, err = w.remapPostingsList(pl)
 			if err != nil {
 				return err
 			}
 
-			n, err := w.writePayloadAndSizeAndMagicNumber(iow, postingsBytes)
+			// serialize the postings list
+			n, err := writeTermPostingsList(iow, w.intEncoder, w.postingsEncoder, pl, w.opts.DocFrequencies)
 			if err != nil {
 				return err
 			}
@@ -224,7 +338,25 @@ func (w *writer) WritePostingsOffsets(iow io.Writer) error {
 
 			// track current offset as the offset for the current field/term
 			w.addPostingsOffset(currentOffset, f, t)
+
+			if fieldPl != nil {
+				if err := fieldPl.Union(pl); err != nil {
+					return err
+				}
+			}
 		}
+
+		// skip the field's postings list if it only appears in deleted documents
+		if fieldPl == nil || fieldPl.IsEmpty() {
+			continue
+		}
+
+		n, err := writePostingsList(iow, w.intEncoder, w.postingsEncoder, fieldPl)
+		if err != nil {
+			return err
+		}
+		currentOffset += n
+		w.addFieldPostingsOffset(currentOffset, f)
 	}
 
 	w.postingsFileWritten = true
@@ -232,6 +364,10 @@ func (w *writer) WritePostingsOffsets(iow io.Writer) error {
 }
 
 func (w *writer) WriteFSTTerms(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeFSTTerms)
+}
+
+func (w *writer) writeFSTTerms(iow io.Writer) error {
 	if !w.postingsFileWritten {
 		return fmt.Errorf("postings offsets have to be written before fst terms can be written")
 	}
@@ -247,11 +383,6 @@ func (w *writer) WriteFSTTerms(iow io.Writer) error {
 
 	// build a fst for each field's terms
 	for _, f := range fields {
-		// reset writer for this field's fst
-		if err := w.fstWriter.Reset(iow); err != nil {
-			return err
-		}
-
 		// retrieve all terms for this field
 		terms, err := w.seg.Terms(f)
 		if err != nil {
@@ -261,8 +392,37 @@ func (w *writer) WriteFSTTerms(iow io.Writer) error {
 		// inserts into the fst have to be lexicographically ordered
 		sortSliceOfByteSlices(terms)
 
+		// skip fields which only appear in deleted documents
+		if !w.hasPostingsOffsets(f, terms) {
+			continue
+		}
+
+		// the offset of the field's postings list precedes the field's fst
+		if w.opts.FieldPostingsLists {
+			po, err := w.getFieldPostingsOffset(f)
+			if err != nil {
+				return err
+			}
+
+			n, err := writeUint64AndSizeAndMagicNumber(iow, w.intEncoder, po)
+			if err != nil {
+				return err
+			}
+			currentOffset += n
+		}
+
+		// reset writer for this field's fst
+		if err := w.fstWriter.Reset(iow); err != nil {
+			return err
+		}
+
 		// for each term corresponding to this field
 		for _, t := range terms {
+			// skip terms which only appear in deleted documents
+			if !w.hasPostingsOffset(f, t) {
+				continue
+			}
+
 			// retieve postsings offset for the current field,term
 			po, err := w.getPostingsOffset(f, t)
 			if err != nil {
@@ -282,7 +442,7 @@ func (w *writer) WriteFSTTerms(iow io.Writer) error {
 		}
 
 		// serialize the size of the fst
-		n, err := w.writeSizeAndMagicNumber(iow, numBytesFST)
+		n, err := writeSizeAndMagicNumber(iow, w.intEncoder, numBytesFST)
 		if err != nil {
 			return err
 		}
@@ -300,6 +460,10 @@ func (w *writer) WriteFSTTerms(iow io.Writer) error {
 }
 
 func (w *writer) WriteFSTFields(iow io.Writer) error {
+	return writeFile(iow, w.intEncoder, w.opts.FileDigests, w.writeFSTFields)
+}
+
+func (w *writer) writeFSTFields(iow io.Writer) error {
 	if !w.fstTermsFileWritten {
 		return fmt.Errorf("fst terms files have to be written before fst fields can be written")
 	}
@@ -320,6 +484,11 @@ func (w *writer) WriteFSTFields(iow io.Writer) error {
 
 	// insert each field into fst
 	for _, f := range fields {
+		// skip fields which only appear in deleted documents
+		if _, ok := w.fstTermsOffsets.Get(f); !ok {
+			continue
+		}
+
 		// get offset for this field's term fst
 		offset, err := w.getFSTTermsOffset(f)
 		if err != nil {
@@ -339,14 +508,18 @@ func (w *writer) WriteFSTFields(iow io.Writer) error {
 
 // given a payload []byte, and io.Writer; this method writes the following data out to the writer
 // | payload - len(payload) bytes | 8 bytes for uint64 (size of payload) | 8 bytes for `magicNumber` |
-func (w *writer) writePayloadAndSizeAndMagicNumber(iow io.Writer, payload []byte) (uint64, error) {
+func writePayloadAndSizeAndMagicNumber(
+	iow io.Writer,
+	enc *encoding.Encoder,
+	payload []byte,
+) (uint64, error) {
 	numBytesWritten := uint64(0)
 	size, err := iow.Write(payload)
 	if err != nil {
 		return 0, err
 	}
 	numBytesWritten += uint64(size)
-	n, err := w.writeSizeAndMagicNumber(iow, uint64(size))
+	n, err := writeSizeAndMagicNumber(iow, enc, uint64(size))
 	if err != nil {
 		return 0, err
 	}
@@ -354,12 +527,60 @@ func (w *writer) writePayloadAndSizeAndMagicNumber(iow io.Writer, payload []byte
 	return numBytesWritten, nil
 }
 
-func (w *writer) writeSizeAndMagicNumber(iow io.Writer, size uint64) (uint64, error) {
+// writeUint64AndSizeAndMagicNumber writes out the provided value as a payload.
+func writeUint64AndSizeAndMagicNumber(iow io.Writer, enc *encoding.Encoder, v uint64) (uint64, error) {
+	enc.Reset()
+	enc.PutUint64(v)
+	payload := append([]byte(nil), enc.Bytes()...)
+	return writePayloadAndSizeAndMagicNumber(iow, enc, payload)
+}
+
+// writePostingsList serializes the provided postings list and writes it out as a payload.
+func writePostingsList(
+	iow io.Writer,
+	enc *encoding.Encoder,
+	postingsEnc *pilosa.Encoder,
+	pl postings.List,
+) (uint64, error) {
+	postingsEnc.Reset()
+	postingsBytes, err := postingsEnc.Encode(pl)
+	if err != nil {
+		return 0, err
+	}
+	return writePayloadAndSizeAndMagicNumber(iow, enc, postingsBytes)
+}
+
+// writeTermPostingsList writes out the postings list of a term, preceded by the number
+// of documents it contains if docFrequency is set.
+func writeTermPostingsList(
+	iow io.Writer,
+	enc *encoding.Encoder,
+	postingsEnc *pilosa.Encoder,
+	pl postings.List,
+	docFrequency bool,
+) (uint64, error) {
+	var numBytesWritten uint64
+	if docFrequency {
+		n, err := writeUint64AndSizeAndMagicNumber(iow, enc, uint64(pl.Len()))
+		if err != nil {
+			return 0, err
+		}
+		numBytesWritten += n
+	}
+
+	n, err := writePostingsList(iow, enc, postingsEnc, pl)
+	if err != nil {
+		return 0, err
+	}
+	return numBytesWritten + n, nil
+}
+
+func writeSizeAndMagicNumber(iow io.Writer, enc *encoding.Encoder, size uint64) (uint64, error) {
 	// serialize the size, magicNumber
-	w.intEncoder.Reset()
-	w.intEncoder.PutUint64(size)
-	w.intEncoder.PutUint64(uint64(magicNumber))
-	sizeBytes := w.intEncoder.Bytes()
+	enc.Reset()
+	enc.PutUint64(size)
+	enc.PutUint64(uint64(magicNumber))
+	sizeBytes := enc.Bytes()
 
 	// write out the size
 	n, err := iow.Write(sizeBytes)
@@ -384,6 +605,21 @@ func (w *writer) getFSTTermsOffset(field []byte) (uint64, error) {
 	return offset, nil
 }
 
+func (w *writer) addFieldPostingsOffset(offset uint64, field []byte) {
+	w.fieldPostingsOffsets.SetUnsafe(field, offset, fstTermsOffsetsMapSetUnsafeOptions{
+		NoCopyKey:     true,
+		NoFinalizeKey: true,
+	})
+}
+
+func (w *writer) getFieldPostingsOffset(field []byte) (uint64, error) {
+	offset, ok := w.fieldPostingsOffsets.Get(field)
+	if !ok {
+		return 0, errUnableToFindFieldPostingsOffset
+	}
+	return offset, nil
+}
+
 func (w *writer) addPostingsOffset(offset uint64, name, value []byte) {
 	field := doc.Field{
 		Name:  name,
@@ -395,6 +631,24 @@ func (w *writer) addPostingsOffset(offset uint64, name, value []byte) {
 	})
 }
 
+func (w *writer) hasPostingsOffset(name, value []byte) bool {
+	field := doc.Field{
+		Name:  name,
+		Value: value,
+	}
+	_, ok := w.postingsOffsets.Get(field)
+	return ok
+}
+
+func (w *writer) hasPostingsOffsets(name []byte, values [][]byte) bool {
+	for _, v := range values {
+		if w.hasPostingsOffset(name, v) {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *writer) getPostingsOffset(name, value []byte) (uint64, error) {
 	field := doc.Field{
 		Name:  name,
@@ -23,11 +23,13 @@ package fs
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"sort"
 	"sync"
 	"testing"
 
 	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
 	sgmt "github.com/m3db/m3ninx/index/segment"
 	"github.com/m3db/m3ninx/index/segment/mem"
 	"github.com/m3db/m3ninx/index/util"
@@ -265,7 +267,7 @@ func TestPostingsListRegexAll(t *testing.T) {
 			for _, f := range fields {
 				reader, err := memSeg.Reader()
 				require.NoError(t, err)
-				memPl, err := reader.MatchRegexp(f, []byte("."), nil)
+				memPl, err := reader.MatchRegexp(f, []byte(".*"), nil)
 				require.NoError(t, err)
 
 				fstReader, err := fstSeg.Reader()
@@ -278,6 +280,366 @@ func TestPostingsListRegexAll(t *testing.T) {
 	}
 }
 
+func TestPostingsListPrefix(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			fields, err := memSeg.Fields()
+			require.NoError(t, err)
+
+			reader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			for _, f := range fields {
+				terms, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				for _, term := range terms {
+					prefix := term[:len(term)/2]
+
+					memPl, err := reader.MatchPrefix(f, prefix)
+					require.NoError(t, err)
+					fstPl, err := fstReader.MatchPrefix(f, prefix)
+					require.NoError(t, err)
+					require.True(t, memPl.Equal(fstPl))
+
+					regexpPl, err := fstReader.MatchRegexp(f, []byte(regexp.QuoteMeta(string(prefix))+".*"), nil)
+					require.NoError(t, err)
+					require.True(t, regexpPl.Equal(fstPl))
+				}
+			}
+
+			require.NoError(t, reader.Close())
+			require.NoError(t, fstReader.Close())
+		})
+	}
+}
+
+func TestPostingsListTermRange(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			fields, err := memSeg.Fields()
+			require.NoError(t, err)
+
+			reader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			for _, f := range fields {
+				terms, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				sortSliceOfByteSlices(terms)
+
+				bounds := [][2][]byte{
+					{nil, nil},
+					{terms[0], terms[len(terms)-1]},
+					{terms[len(terms)/2], nil},
+					{nil, terms[len(terms)/2]},
+				}
+				for _, b := range bounds {
+					for _, inclusive := range [][2]bool{{true, true}, {false, false}, {true, false}} {
+						memPl, err := reader.MatchTermRange(f, b[0], b[1], inclusive[0], inclusive[1])
+						require.NoError(t, err)
+						fstPl, err := fstReader.MatchTermRange(f, b[0], b[1], inclusive[0], inclusive[1])
+						require.NoError(t, err)
+						require.True(t, memPl.Equal(fstPl))
+					}
+				}
+
+				// A closed range over all of the terms matches every document with the field.
+				allPl, err := fstReader.MatchRegexp(f, []byte(".*"), nil)
+				require.NoError(t, err)
+				fstPl, err := fstReader.MatchTermRange(f, terms[0], terms[len(terms)-1], true, true)
+				require.NoError(t, err)
+				require.True(t, allPl.Equal(fstPl))
+			}
+
+			require.NoError(t, reader.Close())
+			require.NoError(t, fstReader.Close())
+		})
+	}
+}
+
+func TestPostingsListTerms(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			fields, err := memSeg.Fields()
+			require.NoError(t, err)
+
+			reader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			for _, f := range fields {
+				terms, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				sortSliceOfByteSlices(terms)
+
+				var everyOther, withMissing [][]byte
+				for i, term := range terms {
+					if i%2 == 0 {
+						everyOther = append(everyOther, term)
+					}
+					withMissing = append(withMissing, term, append(append([]byte(nil), term...), "-missing"...))
+				}
+				withMissing = append(withMissing, []byte(""), []byte("\xff\xff"))
+				sortSliceOfByteSlices(withMissing)
+
+				for _, ts := range [][][]byte{nil, terms, everyOther, withMissing, terms[len(terms)-1:]} {
+					memPl, err := reader.MatchTerms(f, ts)
+					require.NoError(t, err)
+					fstPl, err := fstReader.MatchTerms(f, ts)
+					require.NoError(t, err)
+					require.True(t, memPl.Equal(fstPl))
+
+					// The result should match the union of the individual term matches.
+					expected := roaring.NewPostingsList()
+					for _, term := range ts {
+						pl, err := fstReader.MatchTerm(f, term)
+						require.NoError(t, err)
+						require.NoError(t, expected.Union(pl))
+					}
+					require.True(t, expected.Equal(fstPl))
+				}
+			}
+
+			require.NoError(t, reader.Close())
+			require.NoError(t, fstReader.Close())
+		})
+	}
+}
+
+func TestFieldTerms(t *testing.T) {
+	for _, test := range testDocuments {
+		t.Run(test.name, func(t *testing.T) {
+			memSeg, fstSeg := newTestSegments(t, test.docs)
+			fields, err := memSeg.Fields()
+			require.NoError(t, err)
+			sortSliceOfByteSlices(fields)
+			fields = append(fields, []byte("unknown"))
+
+			memReader, err := memSeg.Reader()
+			require.NoError(t, err)
+			fstReader, err := fstSeg.Reader()
+			require.NoError(t, err)
+
+			for _, r := range []index.Reader{memReader, fstReader} {
+				names, err := r.FieldNames()
+				require.NoError(t, err)
+				sortSliceOfByteSlices(names)
+				require.Equal(t, fields[:len(fields)-1], names)
+			}
+
+			for _, f := range fields {
+				terms, err := memSeg.Terms(f)
+				require.NoError(t, err)
+				sortSliceOfByteSlices(terms)
+
+				for _, r := range []index.Reader{memReader, fstReader} {
+					iter, err := r.FieldTerms(f)
+					require.NoError(t, err)
+
+					var actual [][]byte
+					for iter.Next() {
+						term, pl := iter.Current()
+						expected, err := r.MatchTerm(f, term)
+						require.NoError(t, err)
+						require.True(t, expected.Equal(pl))
+						actual = append(actual, append([]byte(nil), term...))
+					}
+					require.NoError(t, iter.Err())
+					require.NoError(t, iter.Close())
+
+					if len(terms) == 0 {
+						require.Empty(t, actual)
+						continue
+					}
+					require.Equal(t, terms, actual)
+				}
+			}
+
+			require.NoError(t, memReader.Close())
+			require.NoError(t, fstReader.Close())
+		})
+	}
+}
+
+func TestFieldTermsSegmentClosed(t *testing.T) {
+	_, fstSeg := newTestSegments(t, testDocuments[0].docs)
+	field := testDocuments[0].docs[0].Fields[0].Name
+
+	iter, err := fstSeg.(Segment).FieldTerms(field)
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+
+	require.NoError(t, fstSeg.Close())
+	require.False(t, iter.Next())
+	require.Equal(t, errReaderClosed, iter.Err())
+	require.NoError(t, iter.Close())
+}
+
+func TestPostingsListField(t *testing.T) {
+	for _, test := range testDocuments {
+		for _, opts := range []WriterOpts{{}, {FieldPostingsLists: true}} {
+			name := fmt.Sprintf("%s, field postings lists: %v", test.name, opts.FieldPostingsLists)
+			t.Run(name, func(t *testing.T) {
+				memSeg := newTestMemSegment(t)
+				for _, d := range test.docs {
+					_, err := memSeg.Insert(d)
+					require.NoError(t, err)
+				}
+				fstSeg := newFSTSegmentWithOpts(t, memSeg, opts)
+
+				// Merging preserves the field postings lists.
+				mw := NewMergeWriter(opts)
+				require.NoError(t, mw.Reset([]Segment{fstSeg.(Segment)}))
+				mergedSeg := newSegmentFromWriter(t, mw)
+
+				fields, err := memSeg.Fields()
+				require.NoError(t, err)
+				fields = append(fields, []byte("unknown"))
+
+				reader, err := memSeg.Reader()
+				require.NoError(t, err)
+				for _, seg := range []sgmt.Segment{fstSeg, mergedSeg} {
+					fstReader, err := seg.Reader()
+					require.NoError(t, err)
+					for _, f := range fields {
+						memPl, err := reader.MatchField(f)
+						require.NoError(t, err)
+						fstPl, err := fstReader.MatchField(f)
+						require.NoError(t, err)
+						require.True(t, memPl.Equal(fstPl))
+
+						regexpPl, err := fstReader.MatchRegexp(f, []byte(".*"), nil)
+						require.NoError(t, err)
+						require.True(t, regexpPl.Equal(fstPl))
+					}
+					require.NoError(t, fstReader.Close())
+				}
+				require.NoError(t, reader.Close())
+			})
+		}
+	}
+}
+
+func TestPostingsListFieldExcludesDeletedDocuments(t *testing.T) {
+	_, fstSeg := newTestSegments(t, fewTestDocuments)
+
+	r, err := fstSeg.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.NoError(t, fstSeg.DeletePostings(pl))
+	require.NoError(t, r.Close())
+
+	w := NewWriterWithOpts(WriterOpts{FieldPostingsLists: true})
+	require.NoError(t, w.Reset(fstSeg))
+	rewritten := newSegmentFromWriter(t, w)
+
+	r, err = rewritten.Reader()
+	require.NoError(t, err)
+	pl, err = r.MatchField([]byte("fruit"))
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	// Deleting a document from the rewritten segment excludes it from the field's postings.
+	pl, err = r.MatchTerm([]byte("fruit"), []byte("banana"))
+	require.NoError(t, err)
+	require.NoError(t, rewritten.DeletePostings(pl))
+	require.NoError(t, r.Close())
+
+	r, err = rewritten.Reader()
+	require.NoError(t, err)
+	pl, err = r.MatchField([]byte("color"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	require.NoError(t, r.Close())
+}
+
+func TestDocFrequency(t *testing.T) {
+	for _, test := range testDocuments {
+		for _, opts := range []WriterOpts{
+			{},
+			{DocFrequencies: true},
+			{DocFrequencies: true, FieldPostingsLists: true},
+		} {
+			name := fmt.Sprintf("%s, doc frequencies: %v, field postings lists: %v",
+				test.name, opts.DocFrequencies, opts.FieldPostingsLists)
+			t.Run(name, func(t *testing.T) {
+				memSeg := newTestMemSegment(t)
+				for _, d := range test.docs {
+					_, err := memSeg.Insert(d)
+					require.NoError(t, err)
+				}
+				fstSeg := newFSTSegmentWithOpts(t, memSeg, opts)
+
+				// Merging preserves the document frequencies.
+				mw := NewMergeWriter(opts)
+				require.NoError(t, mw.Reset([]Segment{fstSeg.(Segment)}))
+				mergedSeg := newSegmentFromWriter(t, mw)
+
+				fields, err := memSeg.Fields()
+				require.NoError(t, err)
+				fields = append(fields, []byte("unknown"))
+
+				reader, err := memSeg.Reader()
+				require.NoError(t, err)
+				for _, seg := range []sgmt.Segment{fstSeg, mergedSeg} {
+					fstReader, err := seg.Reader()
+					require.NoError(t, err)
+					for _, f := range fields {
+						terms, err := memSeg.Terms(f)
+						require.NoError(t, err)
+						terms = append(terms, []byte("unknown"))
+
+						for _, term := range terms {
+							memPl, err := reader.MatchTerm(f, term)
+							require.NoError(t, err)
+							// Without the document frequencies only missing terms can be
+							// estimated without retrieving their postings lists.
+							freq, err := fstReader.DocFrequency(f, term)
+							if !opts.DocFrequencies && !memPl.IsEmpty() {
+								require.Equal(t, index.ErrDocFrequencyUnavailable, err)
+							} else {
+								require.NoError(t, err)
+								require.Equal(t, memPl.Len(), freq, "%s=%s", f, term)
+							}
+
+							fstPl, err := fstReader.MatchTerm(f, term)
+							require.NoError(t, err)
+							require.True(t, memPl.Equal(fstPl))
+						}
+					}
+					require.NoError(t, fstReader.Close())
+				}
+				require.NoError(t, reader.Close())
+			})
+		}
+	}
+}
+
+func TestPrefixEnd(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{prefix: nil, expected: nil},
+		{prefix: []byte("abc"), expected: []byte("abd")},
+		{prefix: []byte{'a', 0xff}, expected: []byte("b")},
+		{prefix: []byte{0xff, 0xff}, expected: nil},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, prefixEnd(test.prefix))
+	}
+}
+
 func TestSegmentDocs(t *testing.T) {
 	for _, test := range testDocuments {
 		t.Run(test.name, func(t *testing.T) {
@@ -355,6 +717,122 @@ func TestPostingsListReaderLifecycle(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSegmentDeletePostings(t *testing.T) {
+	_, fstSeg := newTestSegments(t, fewTestDocuments)
+
+	before, err := fstSeg.Reader()
+	require.NoError(t, err)
+
+	pl, err := before.MatchTerm([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	require.NoError(t, fstSeg.DeletePostings(pl))
+
+	ok, err := fstSeg.ContainsID([]byte("42"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	after, err := fstSeg.Reader()
+	require.NoError(t, err)
+
+	// The reader created before the deletion still sees the documents.
+	pl, err = before.MatchAll()
+	require.NoError(t, err)
+	require.Equal(t, 3, pl.Len())
+	_, err = before.Doc(2)
+	require.NoError(t, err)
+
+	pl, err = after.MatchAll()
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	_, err = after.Doc(2)
+	require.Equal(t, index.ErrDocNotFound, err)
+
+	pl, err = after.MatchRegexp([]byte("fruit"), []byte(".*"), nil)
+	require.NoError(t, err)
+	docs, err := after.Docs(pl)
+	require.NoError(t, err)
+	actual, err := collectDocs(docs)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, fewTestDocuments[1].Fields, actual[0].Fields)
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+}
+
+func TestWriterDropsDeletedDocuments(t *testing.T) {
+	memSeg := newTestMemSegment(t)
+	for _, d := range fewTestDocuments {
+		_, err := memSeg.Insert(d)
+		require.NoError(t, err)
+	}
+
+	r, err := memSeg.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.NoError(t, memSeg.DeletePostings(pl))
+	require.NoError(t, r.Close())
+
+	fstSeg := newFSTSegment(t, memSeg)
+	require.Equal(t, int64(2), fstSeg.Size())
+
+	terms, err := fstSeg.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, [][]byte{[]byte("banana"), []byte("pineapple")}, terms)
+
+	terms, err = fstSeg.Terms([]byte("color"))
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, [][]byte{[]byte("yellow")}, terms)
+
+	// The postings IDs of the remaining documents are contiguous.
+	fstReader, err := fstSeg.Reader()
+	require.NoError(t, err)
+	pl, err = fstReader.MatchTerm([]byte("fruit"), []byte("pineapple"))
+	require.NoError(t, err)
+	require.True(t, pl.Contains(1))
+
+	iter, err := fstReader.AllDocs()
+	require.NoError(t, err)
+	actual, err := collectDocs(iter)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, fewTestDocuments[0].Fields, actual[0].Fields)
+	require.Equal(t, fewTestDocuments[2], actual[1])
+	require.NoError(t, fstReader.Close())
+}
+
+func TestWriterRewritesSegment(t *testing.T) {
+	_, fstSeg := newTestSegments(t, fewTestDocuments)
+
+	r, err := fstSeg.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.NoError(t, fstSeg.DeletePostings(pl))
+	require.NoError(t, r.Close())
+
+	w := NewWriter()
+	require.NoError(t, w.Reset(fstSeg))
+	rewritten := newSegmentFromWriter(t, w)
+	require.Equal(t, int64(2), rewritten.Size())
+
+	terms, err := rewritten.Terms([]byte("fruit"))
+	require.NoError(t, err)
+	assertSliceOfByteSlicesEqual(t, [][]byte{[]byte("banana"), []byte("pineapple")}, terms)
+
+	rewrittenReader, err := rewritten.Reader()
+	require.NoError(t, err)
+	iter, err := rewrittenReader.AllDocs()
+	require.NoError(t, err)
+	actual, err := collectDocs(iter)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, fewTestDocuments[0].Fields, actual[0].Fields)
+	require.Equal(t, fewTestDocuments[2], actual[1])
+	require.NoError(t, rewrittenReader.Close())
+}
+
 func newTestSegments(t *testing.T, docs []doc.Document) (memSeg sgmt.MutableSegment, fstSeg sgmt.Segment) {
 	s := newTestMemSegment(t)
 	for _, d := range docs {
@@ -372,12 +850,33 @@ func newTestMemSegment(t *testing.T) sgmt.MutableSegment {
 }
 
 func newFSTSegment(t *testing.T, s sgmt.MutableSegment) sgmt.Segment {
+	return newFSTSegmentWithOpts(t, s, WriterOpts{})
+}
+
+func newFSTSegmentWithOpts(t *testing.T, s sgmt.MutableSegment, opts WriterOpts) sgmt.Segment {
 	_, err := s.Seal()
 	require.NoError(t, err)
 
-	w := NewWriter()
+	w := NewWriterWithOpts(opts)
 	require.NoError(t, w.Reset(s))
 
+	return newSegmentFromWriter(t, w)
+}
+
+func newSegmentFromWriter(t *testing.T, w FilesWriter) sgmt.Segment {
+	return newSegmentFromWriterWithOpts(t, w, NewSegmentOpts{
+		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+	})
+}
+
+func newSegmentFromWriterWithOpts(t *testing.T, w FilesWriter, opts NewSegmentOpts) sgmt.Segment {
+	reader, err := NewSegment(newSegmentDataFromWriter(t, w), opts)
+	require.NoError(t, err)
+
+	return reader
+}
+
+func newSegmentDataFromWriter(t *testing.T, w FilesWriter) SegmentData {
 	var (
 		docsDataBuffer  bytes.Buffer
 		docsIndexBuffer bytes.Buffer
@@ -392,7 +891,7 @@ func newFSTSegment(t *testing.T, s sgmt.MutableSegment) sgmt.Segment {
 	require.NoError(t, w.WriteFSTTerms(&fstTermsBuffer))
 	require.NoError(t, w.WriteFSTFields(&fstFieldsBuffer))
 
-	data := SegmentData{
+	return SegmentData{
 		MajorVersion:  w.MajorVersion(),
 		MinorVersion:  w.MinorVersion(),
 		Metadata:      w.Metadata(),
@@ -402,13 +901,6 @@ func newFSTSegment(t *testing.T, s sgmt.MutableSegment) sgmt.Segment {
 		FSTTermsData:  fstTermsBuffer.Bytes(),
 		FSTFieldsData: fstFieldsBuffer.Bytes(),
 	}
-	opts := NewSegmentOpts{
-		PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
-	}
-	reader, err := NewSegment(data, opts)
-	require.NoError(t, err)
-
-	return reader
 }
 
 func assertSliceOfByteSlicesEqual(t *testing.T, a, b [][]byte) {
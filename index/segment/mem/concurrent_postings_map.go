@@ -21,7 +21,9 @@
 package mem
 
 import (
+	"bytes"
 	"regexp"
+	"sort"
 	"sync"
 
 	"github.com/m3db/m3ninx/postings"
@@ -32,6 +34,13 @@ type concurrentPostingsMap struct {
 	sync.RWMutex
 	*postingsMap
 
+	// sortedKeys contains the keys of the map in ascending order except for those added
+	// since the keys were last sorted which are in unsortedKeys. The keys are sorted
+	// lazily so adding a key remains cheap, at the cost of the first prefix or range
+	// match after new keys are added, see sortKeys.
+	sortedKeys   [][]byte
+	unsortedKeys [][]byte
+
 	opts Options
 }
 
@@ -73,6 +82,7 @@ func (m *concurrentPostingsMap) Add(key []byte, id postings.ID) {
 		NoCopyKey:     true,
 		NoFinalizeKey: true,
 	})
+	m.unsortedKeys = append(m.unsortedKeys, key)
 	m.Unlock()
 	p.Insert(id)
 }
@@ -88,6 +98,14 @@ func (m *concurrentPostingsMap) Keys() [][]byte {
 	return keys
 }
 
+// SortedKeys returns the keys known to the map in ascending order. The returned slice
+// must not be modified.
+func (m *concurrentPostingsMap) SortedKeys() [][]byte {
+	m.rlockSorted()
+	defer m.RUnlock()
+	return m.sortedKeys
+}
+
 // Get returns the postings.List backing `key`.
 func (m *concurrentPostingsMap) Get(key []byte) (postings.List, bool) {
 	m.RLock()
@@ -101,26 +119,172 @@ func (m *concurrentPostingsMap) Get(key []byte) (postings.List, bool) {
 
 // GetRegex returns the union of the postings lists whose keys match the
 // provided regexp.
-func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool) {
+func (m *concurrentPostingsMap) GetRegex(re *regexp.Regexp) (postings.List, bool, error) {
 	var pl postings.MutableList
 
 	m.RLock()
+	defer m.RUnlock()
 	for _, mapEntry := range m.postingsMap.Iter() {
 		// TODO: Evaluate lock contention caused by holding on to the read lock while
 		// evaluating this predicate.
 		// TODO: Evaluate if performing a prefix match would speed up the common case.
 		if re.Match(mapEntry.Key()) {
-			if pl == nil {
-				pl = mapEntry.Value().Clone()
-			} else {
-				pl.Union(mapEntry.Value())
+			var err error
+			if pl, err = union(pl, mapEntry.Value()); err != nil {
+				return nil, false, err
 			}
 		}
 	}
-	m.RUnlock()
 
 	if pl == nil {
-		return nil, false
+		return nil, false, nil
+	}
+	return pl, true, nil
+}
+
+// GetAll returns the union of all of the postings lists in the map.
+func (m *concurrentPostingsMap) GetAll() (postings.List, bool, error) {
+	var pl postings.MutableList
+
+	m.RLock()
+	defer m.RUnlock()
+	for _, mapEntry := range m.postingsMap.Iter() {
+		var err error
+		if pl, err = union(pl, mapEntry.Value()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if pl == nil {
+		return nil, false, nil
+	}
+	return pl, true, nil
+}
+
+// GetPrefix returns the union of the postings lists whose keys begin with the
+// provided prefix.
+func (m *concurrentPostingsMap) GetPrefix(prefix []byte) (postings.List, bool, error) {
+	m.rlockSorted()
+	defer m.RUnlock()
+
+	return m.unionSortedWithRLock(prefix, func(key []byte) bool {
+		return !bytes.HasPrefix(key, prefix)
+	})
+}
+
+// GetRange returns the union of the postings lists whose keys are within the provided
+// range. An empty bound leaves that side of the range open.
+func (m *concurrentPostingsMap) GetRange(
+	min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, bool, error) {
+	m.rlockSorted()
+	defer m.RUnlock()
+
+	start := min
+	if len(min) > 0 && !minInclusive {
+		// The smallest key greater than min.
+		start = append(append(make([]byte, 0, len(min)+1), min...), 0)
+	}
+	return m.unionSortedWithRLock(start, func(key []byte) bool {
+		if len(max) == 0 {
+			return false
+		}
+		c := bytes.Compare(key, max)
+		return c > 0 || (c == 0 && !maxInclusive)
+	})
+}
+
+// rlockSorted acquires the read lock once all of the keys have been sorted.
+func (m *concurrentPostingsMap) rlockSorted() {
+	m.RLock()
+	for len(m.unsortedKeys) > 0 {
+		m.RUnlock()
+		m.sortKeys()
+		m.RLock()
+	}
+}
+
+// unionSortedWithRLock returns the union of the postings lists of the keys in ascending
+// order starting from the first key greater than or equal to start and ending before
+// the first key for which done returns true. It must be called with the read lock once
+// all of the keys have been sorted.
+func (m *concurrentPostingsMap) unionSortedWithRLock(
+	start []byte,
+	done func(key []byte) bool,
+) (postings.List, bool, error) {
+	var (
+		pl  postings.MutableList
+		idx = sort.Search(len(m.sortedKeys), func(i int) bool {
+			return bytes.Compare(m.sortedKeys[i], start) >= 0
+		})
+	)
+	for _, key := range m.sortedKeys[idx:] {
+		if done(key) {
+			break
+		}
+		p, ok := m.postingsMap.Get(key)
+		if !ok {
+			continue
+		}
+		var err error
+		if pl, err = union(pl, p); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if pl == nil {
+		return nil, false, nil
+	}
+	return pl, true, nil
+}
+
+// sortKeys merges the keys added since the keys were last sorted into the sorted keys.
+// Only the new keys are sorted but merging them copies all of the keys into a new slice,
+// since the previous slice may still be in use by callers of SortedKeys, so it takes
+// O(n + k log k) time for n existing keys and k new keys. It holds the write lock
+// throughout so inserts of new keys and all matches against the map wait for it. For a
+// segment which is inserted into continuously the cost is paid by the first prefix or
+// range match after each batch of new terms.
+func (m *concurrentPostingsMap) sortKeys() {
+	m.Lock()
+	defer m.Unlock()
+	if len(m.unsortedKeys) == 0 {
+		return
+	}
+
+	sort.Slice(m.unsortedKeys, func(i, j int) bool {
+		return bytes.Compare(m.unsortedKeys[i], m.unsortedKeys[j]) < 0
+	})
+
+	var (
+		a, b   = m.sortedKeys, m.unsortedKeys
+		merged = make([][]byte, 0, len(a)+len(b))
+	)
+	for len(a) > 0 && len(b) > 0 {
+		if bytes.Compare(a[0], b[0]) <= 0 {
+			merged = append(merged, a[0])
+			a = a[1:]
+		} else {
+			merged = append(merged, b[0])
+			b = b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	merged = append(merged, b...)
+
+	m.sortedKeys = merged
+	m.unsortedKeys = nil
+}
+
+// union adds the provided postings list to the union pl, which is created from a clone
+// of the postings list if it is nil.
+func union(pl postings.MutableList, other postings.List) (postings.MutableList, error) {
+	if pl == nil {
+		return other.Clone(), nil
+	}
+	if err := pl.Union(other); err != nil {
+		return nil, err
 	}
-	return pl, true
+	return pl, nil
 }
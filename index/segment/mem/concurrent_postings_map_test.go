@@ -26,6 +26,8 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/m3db/m3ninx/postings"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,17 +50,103 @@ func TestConcurrentPostingsMap(t *testing.T) {
 	require.False(t, ok)
 
 	re := regexp.MustCompile("ba.*")
-	pl, ok = pm.GetRegex(re)
+	pl, ok, err := pm.GetRegex(re)
+	require.NoError(t, err)
 	require.True(t, ok)
 	require.Equal(t, 2, pl.Len())
 	require.True(t, pl.Contains(2))
 	require.True(t, pl.Contains(4))
 
 	re = regexp.MustCompile("abc.*")
-	_, ok = pm.GetRegex(re)
+	_, ok, err = pm.GetRegex(re)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	pl, ok, err = pm.GetAll()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 4, pl.Len())
+
+	_, ok, err = newConcurrentPostingsMap(opts).GetAll()
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestConcurrentPostingsMapGetPrefix(t *testing.T) {
+	opts := NewOptions()
+	pm := newConcurrentPostingsMap(opts)
+
+	pm.Add([]byte("foo"), 1)
+	pm.Add([]byte("bar"), 2)
+	pm.Add([]byte("baz"), 3)
+
+	pl, ok, err := pm.GetPrefix([]byte("ba"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, pl.Len())
+	require.True(t, pl.Contains(2))
+	require.True(t, pl.Contains(3))
+
+	// Keys added after a prefix match are included in later matches.
+	pm.Add([]byte("bat"), 4)
+	pm.Add([]byte("a"), 5)
+	pm.Add([]byte("bb"), 6)
+
+	pl, ok, err = pm.GetPrefix([]byte("ba"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 3, pl.Len())
+	require.True(t, pl.Contains(2))
+	require.True(t, pl.Contains(3))
+	require.True(t, pl.Contains(4))
+
+	pl, ok, err = pm.GetPrefix(nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 6, pl.Len())
+
+	_, ok, err = pm.GetPrefix([]byte("c"))
+	require.NoError(t, err)
 	require.False(t, ok)
 }
 
+func TestConcurrentPostingsMapGetRange(t *testing.T) {
+	opts := NewOptions()
+	pm := newConcurrentPostingsMap(opts)
+
+	pm.Add([]byte("a"), 1)
+	pm.Add([]byte("b"), 2)
+	pm.Add([]byte("c"), 3)
+	pm.Add([]byte("d"), 4)
+
+	tests := []struct {
+		min, max                   []byte
+		minInclusive, maxInclusive bool
+		expected                   []postings.ID
+	}{
+		{min: []byte("b"), max: []byte("c"), minInclusive: true, maxInclusive: true, expected: []postings.ID{2, 3}},
+		{min: []byte("b"), max: []byte("c"), minInclusive: false, maxInclusive: true, expected: []postings.ID{3}},
+		{min: []byte("b"), max: []byte("c"), minInclusive: true, maxInclusive: false, expected: []postings.ID{2}},
+		{min: []byte("b"), max: []byte("c"), minInclusive: false, maxInclusive: false},
+		{min: nil, max: []byte("b"), maxInclusive: true, expected: []postings.ID{1, 2}},
+		{min: []byte("bb"), max: nil, expected: []postings.ID{3, 4}},
+		{min: nil, max: nil, expected: []postings.ID{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		pl, ok, err := pm.GetRange(test.min, test.max, test.minInclusive, test.maxInclusive)
+		require.NoError(t, err)
+		require.Equal(t, len(test.expected) > 0, ok)
+		if !ok {
+			continue
+		}
+		require.Equal(t, len(test.expected), pl.Len())
+		for _, id := range test.expected {
+			require.True(t, pl.Contains(id))
+		}
+	}
+}
+
 func TestConcurrentPostingsMapKeys(t *testing.T) {
 	opts := NewOptions()
 	pm := newConcurrentPostingsMap(opts)
@@ -87,6 +175,7 @@ func TestConcurrentPostingsMapKeys(t *testing.T) {
 	pm.Add(baz, 4)
 	keys = pm.Keys()
 	require.Equal(t, [][]byte{bar, baz, foo}, sortKeys(keys))
+	require.Equal(t, [][]byte{bar, baz, foo}, pm.SortedKeys())
 }
 
 func sortKeys(keys [][]byte) [][]byte {
@@ -29,6 +29,7 @@ import (
 	"regexp"
 
 	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
 	"github.com/m3db/m3ninx/postings"
 
 	"github.com/golang/mock/gomock"
@@ -57,6 +58,32 @@ func (m *MockReadableSegment) EXPECT() *MockReadableSegmentMockRecorder {
 	return m.recorder
 }
 
+// fieldTerms mocks base method
+func (m *MockReadableSegment) fieldTerms(arg0 []byte) (index.TermsIterator, error) {
+	ret := m.ctrl.Call(m, "fieldTerms", arg0)
+	ret0, _ := ret[0].(index.TermsIterator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// fieldTerms indicates an expected call of fieldTerms
+func (mr *MockReadableSegmentMockRecorder) fieldTerms(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "fieldTerms", reflect.TypeOf((*MockReadableSegment)(nil).fieldTerms), arg0)
+}
+
+// fields mocks base method
+func (m *MockReadableSegment) fields() ([][]byte, error) {
+	ret := m.ctrl.Call(m, "fields")
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// fields indicates an expected call of fields
+func (mr *MockReadableSegmentMockRecorder) fields() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "fields", reflect.TypeOf((*MockReadableSegment)(nil).fields))
+}
+
 // getDoc mocks base method
 func (m *MockReadableSegment) getDoc(arg0 postings.ID) (doc.Document, error) {
 	ret := m.ctrl.Call(m, "getDoc", arg0)
@@ -70,6 +97,32 @@ func (mr *MockReadableSegmentMockRecorder) getDoc(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getDoc", reflect.TypeOf((*MockReadableSegment)(nil).getDoc), arg0)
 }
 
+// matchField mocks base method
+func (m *MockReadableSegment) matchField(arg0 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "matchField", arg0)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchField indicates an expected call of matchField
+func (mr *MockReadableSegmentMockRecorder) matchField(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchField", reflect.TypeOf((*MockReadableSegment)(nil).matchField), arg0)
+}
+
+// matchPrefix mocks base method
+func (m *MockReadableSegment) matchPrefix(arg0, arg1 []byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "matchPrefix", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchPrefix indicates an expected call of matchPrefix
+func (mr *MockReadableSegmentMockRecorder) matchPrefix(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchPrefix", reflect.TypeOf((*MockReadableSegment)(nil).matchPrefix), arg0, arg1)
+}
+
 // matchRegexp mocks base method
 func (m *MockReadableSegment) matchRegexp(arg0, arg1 []byte, arg2 *regexp.Regexp) (postings.List, error) {
 	ret := m.ctrl.Call(m, "matchRegexp", arg0, arg1, arg2)
@@ -95,3 +148,29 @@ func (m *MockReadableSegment) matchTerm(arg0, arg1 []byte) (postings.List, error
 func (mr *MockReadableSegmentMockRecorder) matchTerm(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerm", reflect.TypeOf((*MockReadableSegment)(nil).matchTerm), arg0, arg1)
 }
+
+// matchTermRange mocks base method
+func (m *MockReadableSegment) matchTermRange(arg0, arg1, arg2 []byte, arg3, arg4 bool) (postings.List, error) {
+	ret := m.ctrl.Call(m, "matchTermRange", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchTermRange indicates an expected call of matchTermRange
+func (mr *MockReadableSegmentMockRecorder) matchTermRange(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTermRange", reflect.TypeOf((*MockReadableSegment)(nil).matchTermRange), arg0, arg1, arg2, arg3, arg4)
+}
+
+// matchTerms mocks base method
+func (m *MockReadableSegment) matchTerms(arg0 []byte, arg1 [][]byte) (postings.List, error) {
+	ret := m.ctrl.Call(m, "matchTerms", arg0, arg1)
+	ret0, _ := ret[0].(postings.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// matchTerms indicates an expected call of matchTerms
+func (mr *MockReadableSegmentMockRecorder) matchTerms(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "matchTerms", reflect.TypeOf((*MockReadableSegment)(nil).matchTerms), arg0, arg1)
+}
@@ -37,9 +37,10 @@ var (
 type reader struct {
 	sync.RWMutex
 
-	segment ReadableSegment
-	limits  readerDocRange
-	plPool  postings.Pool
+	segment    ReadableSegment
+	limits     readerDocRange
+	tombstones postings.List
+	plPool     postings.Pool
 
 	closed bool
 }
@@ -49,11 +50,20 @@ type readerDocRange struct {
 	endExclusive   postings.ID
 }
 
-func newReader(s ReadableSegment, l readerDocRange, p postings.Pool) index.Reader {
+// newReader returns a new reader over the segment. The tombstones are the postings IDs
+// of the documents which have been deleted from the segment, they may be nil if there
+// are none.
+func newReader(
+	s ReadableSegment,
+	l readerDocRange,
+	tombstones postings.List,
+	p postings.Pool,
+) index.Reader {
 	return &reader{
-		segment: s,
-		limits:  l,
-		plPool:  p,
+		segment:    s,
+		limits:     l,
+		tombstones: tombstones,
+		plPool:     p,
 	}
 }
 
@@ -64,12 +74,39 @@ func (r *reader) MatchTerm(field, term []byte) (postings.List, error) {
 		return nil, errSegmentReaderClosed
 	}
 
-	// A reader can return IDs in the posting list which are greater than its limit.
-	// The reader only guarantees that when fetching the documents associated with a
-	// postings list through a call to Docs, IDs greater than or equal to the limit
-	// will be filtered out.
 	pl, err := r.segment.matchTerm(field, term)
-	return pl, err
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) MatchTerms(field []byte, terms [][]byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	pl, err := r.segment.matchTerms(field, terms)
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) DocFrequency(field, term []byte) (int, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return 0, errSegmentReaderClosed
+	}
+
+	pl, err := r.segment.matchTerm(field, term)
+	if err != nil {
+		return 0, err
+	}
+	return pl.Len(), nil
 }
 
 func (r *reader) MatchRegexp(field, regexp []byte, compiled *regexp.Regexp) (postings.List, error) {
@@ -79,12 +116,76 @@ func (r *reader) MatchRegexp(field, regexp []byte, compiled *regexp.Regexp) (pos
 		return nil, errSegmentReaderClosed
 	}
 
-	// A reader can return IDs in the posting list which are greater than its maximum
-	// permitted ID. The reader only guarantees that when fetching the documents associated
-	// with a postings list through a call to Docs will IDs greater than the maximum be
-	// filtered out.
 	pl, err := r.segment.matchRegexp(field, regexp, compiled)
-	return pl, err
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) MatchField(field []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	pl, err := r.segment.matchField(field)
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	pl, err := r.segment.matchPrefix(field, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) MatchTermRange(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	pl, err := r.segment.matchTermRange(field, min, max, minInclusive, maxInclusive)
+	if err != nil {
+		return nil, err
+	}
+	return r.visible(pl)
+}
+
+func (r *reader) FieldNames() ([][]byte, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	return r.segment.fields()
+}
+
+func (r *reader) FieldTerms(field []byte) (index.TermsIterator, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if r.closed {
+		return nil, errSegmentReaderClosed
+	}
+
+	return r.segment.fieldTerms(field)
 }
 
 func (r *reader) MatchAll() (postings.MutableList, error) {
@@ -96,6 +197,11 @@ func (r *reader) MatchAll() (postings.MutableList, error) {
 
 	pl := r.plPool.Get()
 	pl.AddRange(r.limits.startInclusive, r.limits.endExclusive)
+	if r.tombstones != nil {
+		if err := pl.Difference(r.tombstones); err != nil {
+			return nil, err
+		}
+	}
 	return pl, nil
 }
 
@@ -110,17 +216,21 @@ func (r *reader) Doc(id postings.ID) (doc.Document, error) {
 		return doc.Document{}, index.ErrDocNotFound
 	}
 
+	if r.tombstones != nil && r.tombstones.Contains(id) {
+		return doc.Document{}, index.ErrDocNotFound
+	}
+
 	return r.segment.getDoc(id)
 }
 
-func (r *reader) Docs(pl postings.List) (doc.Iterator, error) {
+func (r *reader) Docs(pl postings.List) (index.IDDocIterator, error) {
 	r.RLock()
 	defer r.RUnlock()
 	if r.closed {
 		return nil, errSegmentReaderClosed
 	}
 	boundedIter := newBoundedPostingsIterator(pl.Iterator(), r.limits)
-	return r.getDocIterWithLock(boundedIter), nil
+	return r.getDocIterWithLock(r.excludeTombstonesIter(boundedIter)), nil
 }
 
 func (r *reader) AllDocs() (index.IDDocIterator, error) {
@@ -131,7 +241,32 @@ func (r *reader) AllDocs() (index.IDDocIterator, error) {
 	}
 
 	pi := postings.NewRangeIterator(r.limits.startInclusive, r.limits.endExclusive)
-	return r.getDocIterWithLock(pi), nil
+	return r.getDocIterWithLock(r.excludeTombstonesIter(pi)), nil
+}
+
+// visible returns the provided postings list without any deleted documents or IDs
+// outside of the reader's limits, i.e. only the documents visible to the reader.
+func (r *reader) visible(pl postings.List) (postings.List, error) {
+	// NB: the postings lists returned by the segment are shared and may continue to have
+	// IDs inserted into them so we must take a copy rather than return them directly.
+	clone := pl.Clone()
+	clone.RemoveRange(0, r.limits.startInclusive)
+	clone.RemoveRange(r.limits.endExclusive, postings.MaxID)
+	if r.tombstones != nil {
+		if err := clone.Difference(r.tombstones); err != nil {
+			return nil, err
+		}
+	}
+	return clone, nil
+}
+
+// excludeTombstonesIter returns an iterator over the IDs in the provided iterator
+// without any deleted documents.
+func (r *reader) excludeTombstonesIter(iter postings.Iterator) postings.Iterator {
+	if r.tombstones == nil {
+		return iter
+	}
+	return postings.NewDifferenceIterator(iter, r.tombstones)
 }
 
 func (r *reader) getDocIterWithLock(iter postings.Iterator) index.IDDocIterator {
@@ -49,11 +49,43 @@ func TestReaderMatchExact(t *testing.T) {
 		segment.EXPECT().matchTerm(name, value).Return(postingsList, nil),
 	)
 
-	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	reader := newReader(segment, readerDocRange{0, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
+
+	// IDs greater than or equal to the reader's limit should be excluded.
+	expected := roaring.NewPostingsList()
+	expected.Insert(postings.ID(42))
+	expected.Insert(postings.ID(50))
 
 	actual, err := reader.MatchTerm(name, value)
 	require.NoError(t, err)
-	require.True(t, postingsList.Equal(actual))
+	require.True(t, expected.Equal(actual))
+
+	require.NoError(t, reader.Close())
+}
+
+func TestReaderDocFrequency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	maxID := postings.ID(55)
+
+	name, value := []byte("apple"), []byte("red")
+	postingsList := roaring.NewPostingsList()
+	postingsList.Insert(postings.ID(42))
+	postingsList.Insert(postings.ID(50))
+	postingsList.Insert(postings.ID(57))
+
+	segment := NewMockReadableSegment(mockCtrl)
+	gomock.InOrder(
+		segment.EXPECT().matchTerm(name, value).Return(postingsList, nil),
+	)
+
+	reader := newReader(segment, readerDocRange{0, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
+
+	// The document frequency is an estimate which includes IDs outside of the reader's range.
+	freq, err := reader.DocFrequency(name, value)
+	require.NoError(t, err)
+	require.Equal(t, 3, freq)
 
 	require.NoError(t, reader.Close())
 }
@@ -76,11 +108,16 @@ func TestReaderMatchRegex(t *testing.T) {
 		segment.EXPECT().matchRegexp(name, regexp, compiled).Return(postingsList, nil),
 	)
 
-	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	reader := newReader(segment, readerDocRange{0, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
+
+	// IDs greater than or equal to the reader's limit should be excluded.
+	expected := roaring.NewPostingsList()
+	expected.Insert(postings.ID(42))
+	expected.Insert(postings.ID(50))
 
 	actual, err := reader.MatchRegexp(name, regexp, compiled)
 	require.NoError(t, err)
-	require.True(t, postingsList.Equal(actual))
+	require.True(t, expected.Equal(actual))
 
 	require.NoError(t, reader.Close())
 }
@@ -101,7 +138,7 @@ func TestReaderMatchAll(t *testing.T) {
 	postingsList.Insert(postings.ID(45))
 	postingsList.Insert(postings.ID(46))
 
-	reader := newReader(nil, readerDocRange{minID, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	reader := newReader(nil, readerDocRange{minID, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
 
 	actual, err := reader.MatchAll()
 	require.NoError(t, err)
@@ -145,7 +182,7 @@ func TestReaderDocs(t *testing.T) {
 	postingsList.Insert(postings.ID(47))
 	postingsList.Insert(postings.ID(57)) // IDs past maxID should be ignored.
 
-	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	reader := newReader(segment, readerDocRange{0, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
 
 	iter, err := reader.Docs(postingsList)
 	require.NoError(t, err)
@@ -193,7 +230,7 @@ func TestReaderAllDocs(t *testing.T) {
 		segment.EXPECT().getDoc(postings.ID(1)).Return(docs[1], nil),
 	)
 
-	reader := newReader(segment, readerDocRange{0, maxID}, postings.NewPool(nil, roaring.NewPostingsList))
+	reader := newReader(segment, readerDocRange{0, maxID}, nil, postings.NewPool(nil, roaring.NewPostingsList))
 	iter, err := reader.AllDocs()
 	require.NoError(t, err)
 
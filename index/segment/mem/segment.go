@@ -58,29 +58,41 @@ type segment struct {
 	// Mapping of term to postings list.
 	termsDict termsDictionary
 
+	// Postings IDs of the documents which have been deleted.
+	tombstones postings.MutableList
+
 	writer struct {
 		sync.Mutex
 		idSet  *idsMap
 		nextID postings.ID
+
+		// Postings IDs of the existing documents replaced by the batch being inserted.
+		replaced postings.MutableList
 	}
 	readerID postings.AtomicID
+
+	// Held when advancing the reader ID and when creating a reader so that a reader
+	// never sees both a document and the document which replaced it, or neither.
+	snapshot sync.RWMutex
 }
 
 // NewSegment returns a new in-memory mutable segment. It will start assigning
 // postings IDs at the provided offset.
 func NewSegment(offset postings.ID, opts Options) (sgmt.MutableSegment, error) {
 	s := &segment{
-		offset:    int(offset),
-		plPool:    opts.PostingsListPool(),
-		newUUIDFn: opts.NewUUIDFn(),
-		termsDict: newTermsDict(opts),
-		readerID:  postings.NewAtomicID(offset),
+		offset:     int(offset),
+		plPool:     opts.PostingsListPool(),
+		newUUIDFn:  opts.NewUUIDFn(),
+		termsDict:  newTermsDict(opts),
+		tombstones: opts.PostingsListPool().Get(),
+		readerID:   postings.NewAtomicID(offset),
 	}
 
 	s.docs.data = make([]doc.Document, opts.InitialCapacity())
 
 	s.writer.idSet = newIDsMap(256)
 	s.writer.nextID = offset
+	s.writer.replaced = opts.PostingsListPool().Get()
 	return s, nil
 }
 
@@ -108,7 +120,17 @@ func (s *segment) ContainsID(id []byte) (bool, error) {
 }
 
 func (s *segment) containsIDWithStateLock(id []byte) bool {
-	return s.termsDict.ContainsTerm(doc.IDReservedFieldName, id)
+	if s.tombstones.IsEmpty() {
+		return s.termsDict.ContainsTerm(doc.IDReservedFieldName, id)
+	}
+
+	// The segment only contains the ID if at least one of the documents with the ID
+	// has not been deleted.
+	pl := s.termsDict.MatchTerm(doc.IDReservedFieldName, id)
+	iter := postings.NewDifferenceIterator(pl.Iterator(), s.tombstones)
+	contains := iter.Next()
+	iter.Close()
+	return contains
 }
 
 func (s *segment) Insert(d doc.Document) ([]byte, error) {
@@ -123,14 +145,25 @@ func (s *segment) Insert(d doc.Document) ([]byte, error) {
 
 		b := index.NewBatch([]doc.Document{d})
 		if err := s.prepareDocsWithLocks(b); err != nil {
+			s.writer.Unlock()
 			return nil, err
 		}
 
+		// The segment already contains a document with the same ID so there is nothing
+		// to insert.
+		if !b.Docs[0].HasID() {
+			s.writer.Unlock()
+			return d.ID, nil
+		}
+
 		// Update the document in case we generated a UUID for it.
 		d = b.Docs[0]
 
 		s.insertDocWithLocks(d)
-		s.readerID.Inc()
+		if err := s.publishDocsWithLocks(1); err != nil {
+			s.writer.Unlock()
+			return nil, err
+		}
 
 		s.writer.Unlock()
 	}
@@ -151,6 +184,7 @@ func (s *segment) InsertBatch(b index.Batch) error {
 
 		err = s.prepareDocsWithLocks(b)
 		if err != nil && !index.IsBatchPartialError(err) {
+			s.writer.Unlock()
 			return err
 		}
 
@@ -164,7 +198,10 @@ func (s *segment) InsertBatch(b index.Batch) error {
 			numInserts++
 			s.insertDocWithLocks(d)
 		}
-		s.readerID.Add(numInserts)
+		if publishErr := s.publishDocsWithLocks(numInserts); publishErr != nil {
+			s.writer.Unlock()
+			return publishErr
+		}
 
 		s.writer.Unlock()
 	}
@@ -176,6 +213,7 @@ func (s *segment) InsertBatch(b index.Batch) error {
 // must be called with the state and writer locks.
 func (s *segment) prepareDocsWithLocks(b index.Batch) error {
 	s.writer.idSet.Reset()
+	s.writer.replaced.Reset()
 	var (
 		batchErr = index.NewBatchPartialError()
 		emptyDoc doc.Document
@@ -193,7 +231,8 @@ func (s *segment) prepareDocsWithLocks(b index.Batch) error {
 		}
 
 		if d.HasID() {
-			if s.containsIDWithStateLock(d.ID) {
+			exists := s.containsIDWithStateLock(d.ID)
+			if exists && !b.Upsert {
 				// The segment already contains this document so we can remove it from those
 				// we need to index.
 				b.Docs[i] = emptyDoc
@@ -208,6 +247,15 @@ func (s *segment) prepareDocsWithLocks(b index.Batch) error {
 				b.Docs[i] = emptyDoc
 				continue
 			}
+
+			if exists {
+				// The existing document is retired once the document replacing it has been
+				// inserted.
+				pl := s.termsDict.MatchTerm(doc.IDReservedFieldName, d.ID)
+				if err := s.writer.replaced.Union(pl); err != nil {
+					return err
+				}
+			}
 		} else {
 			id, err := s.newUUIDFn()
 			if err != nil {
@@ -237,6 +285,24 @@ func (s *segment) prepareDocsWithLocks(b index.Batch) error {
 	return batchErr
 }
 
+// publishDocsWithLocks makes the given number of newly inserted documents visible to
+// readers and retires any existing documents they replaced. It must be called with the
+// state and writer locks.
+func (s *segment) publishDocsWithLocks(numInserts uint32) error {
+	s.snapshot.Lock()
+	defer s.snapshot.Unlock()
+
+	if !s.writer.replaced.IsEmpty() {
+		if err := s.tombstones.Union(s.writer.replaced); err != nil {
+			return err
+		}
+		s.writer.replaced.Reset()
+	}
+
+	s.readerID.Add(numInserts)
+	return nil
+}
+
 // insertDocWithLocks inserts a document into the index. It must be called with the
 // state and writer locks.
 func (s *segment) insertDocWithLocks(d doc.Document) {
@@ -299,6 +365,41 @@ func (s *segment) storeDocWithStateLock(id postings.ID, d doc.Document) {
 	}
 }
 
+func (s *segment) Delete(id []byte) error {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return sgmt.ErrClosed
+	}
+
+	s.writer.Lock()
+	defer s.writer.Unlock()
+
+	pl := s.termsDict.MatchTerm(doc.IDReservedFieldName, id)
+	if pl.IsEmpty() {
+		return nil
+	}
+	return s.tombstones.AddIterator(pl.Iterator())
+}
+
+func (s *segment) DeletePostings(pl postings.List) error {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return sgmt.ErrClosed
+	}
+
+	s.writer.Lock()
+	defer s.writer.Unlock()
+
+	// Only record IDs which have been assigned to a document in this segment.
+	limits := readerDocRange{
+		startInclusive: postings.ID(s.offset),
+		endExclusive:   s.writer.nextID,
+	}
+	return s.tombstones.AddIterator(newBoundedPostingsIterator(pl.Iterator(), limits))
+}
+
 func (s *segment) Reader() (index.Reader, error) {
 	s.state.RLock()
 	defer s.state.RUnlock()
@@ -306,11 +407,21 @@ func (s *segment) Reader() (index.Reader, error) {
 		return nil, sgmt.ErrClosed
 	}
 
+	s.snapshot.RLock()
 	limits := readerDocRange{
 		startInclusive: postings.ID(s.offset),
 		endExclusive:   s.readerID.Load(),
 	}
-	return newReader(s, limits, s.plPool), nil
+
+	// Readers use a snapshot of the tombstones so that deletions made after the reader
+	// is created are not visible to it.
+	var tombstones postings.List
+	if !s.tombstones.IsEmpty() {
+		tombstones = s.tombstones.Clone()
+	}
+	s.snapshot.RUnlock()
+
+	return newReader(s, limits, tombstones, s.plPool), nil
 }
 
 func (s *segment) matchTerm(field, term []byte) (postings.List, error) {
@@ -323,6 +434,16 @@ func (s *segment) matchTerm(field, term []byte) (postings.List, error) {
 	return s.termsDict.MatchTerm(field, term), nil
 }
 
+func (s *segment) matchTerms(field []byte, terms [][]byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchTerms(field, terms)
+}
+
 func (s *segment) matchRegexp(name, regexp []byte, compiled *re.Regexp) (postings.List, error) {
 	s.state.RLock()
 	defer s.state.RUnlock()
@@ -332,12 +453,65 @@ func (s *segment) matchRegexp(name, regexp []byte, compiled *re.Regexp) (posting
 
 	if compiled == nil {
 		var err error
-		compiled, err = re.Compile(string(regexp))
+		compiled, err = index.CompileRegexp(regexp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	return s.termsDict.MatchRegexp(name, regexp, compiled), nil
+	return s.termsDict.MatchRegexp(name, regexp, compiled)
+}
+
+func (s *segment) matchField(field []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchField(field)
+}
+
+func (s *segment) fields() ([][]byte, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.Fields(), nil
+}
+
+func (s *segment) fieldTerms(field []byte) (index.TermsIterator, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.TermsIterator(field), nil
+}
+
+func (s *segment) matchPrefix(field, prefix []byte) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchPrefix(field, prefix)
+}
+
+func (s *segment) matchTermRange(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, error) {
+	s.state.RLock()
+	defer s.state.RUnlock()
+	if s.state.closed {
+		return nil, sgmt.ErrClosed
+	}
+
+	return s.termsDict.MatchTermRange(field, min, max, minInclusive, maxInclusive)
 }
 
 func (s *segment) getDoc(id postings.ID) (doc.Document, error) {
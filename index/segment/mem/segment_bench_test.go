@@ -21,7 +21,6 @@
 package mem
 
 import (
-	"regexp"
 	"testing"
 
 	"github.com/m3db/m3ninx/doc"
@@ -31,7 +30,7 @@ import (
 var (
 	benchSegmentField    = []byte("__name__")
 	benchSegmentRegexp   = []byte("node_netstat_Tcp_.*")
-	benchSegmentCompiled = regexp.MustCompile(string(benchSegmentRegexp))
+	benchSegmentCompiled = mustCompileRegexp(string(benchSegmentRegexp))
 )
 
 func BenchmarkSegment(b *testing.B) {
@@ -26,6 +26,7 @@ import (
 
 	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 
 	"github.com/stretchr/testify/require"
 )
@@ -177,6 +178,12 @@ func TestSegmentInsertDuplicateID(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, int64(1), segment.Size())
 
+	// Inserting a document with the same ID is a no-op.
+	id, err = segment.Insert(second)
+	require.NoError(t, err)
+	require.Equal(t, second.ID, id)
+	require.Equal(t, int64(1), segment.Size())
+
 	r, err := segment.Reader()
 	require.NoError(t, err)
 
@@ -192,6 +199,7 @@ func TestSegmentInsertDuplicateID(t *testing.T) {
 	// Only the first document should be indexed.
 	require.True(t, compareDocs(first, actual))
 	require.False(t, compareDocs(second, actual))
+	require.False(t, iter.Next())
 
 	require.NoError(t, iter.Close())
 	require.NoError(t, r.Close())
@@ -540,6 +548,166 @@ func TestSegmentContainsID(t *testing.T) {
 	require.NoError(t, segment.Close())
 }
 
+func TestSegmentDelete(t *testing.T) {
+	segment, err := NewSegment(0, NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	before, err := segment.Reader()
+	require.NoError(t, err)
+
+	require.NoError(t, segment.Delete([]byte("42")))
+
+	ok, err := segment.ContainsID([]byte("42"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Deleting an ID which doesn't exist is a no-op.
+	require.NoError(t, segment.Delete([]byte("unknown")))
+
+	after, err := segment.Reader()
+	require.NoError(t, err)
+
+	// The reader created before the deletion still sees the document.
+	pl, err := before.MatchTerm([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	pl, err = after.MatchTerm([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	_, err = after.Doc(2)
+	require.Equal(t, index.ErrDocNotFound, err)
+
+	pl, err = after.MatchAll()
+	require.NoError(t, err)
+	require.Equal(t, 2, pl.Len())
+
+	iter, err := after.AllDocs()
+	require.NoError(t, err)
+	var ids []postings.ID
+	for iter.Next() {
+		ids = append(ids, iter.PostingsID())
+	}
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+	require.Equal(t, []postings.ID{0, 1}, ids)
+
+	// The ID can be inserted again once it has been deleted.
+	_, err = segment.Insert(testDocuments[2])
+	require.NoError(t, err)
+	ok, err = segment.ContainsID([]byte("42"))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+	require.NoError(t, segment.Close())
+}
+
+func TestSegmentDeletePostings(t *testing.T) {
+	segment, err := NewSegment(0, NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	r, err := segment.Reader()
+	require.NoError(t, err)
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	require.NoError(t, segment.DeletePostings(pl))
+
+	r, err = segment.Reader()
+	require.NoError(t, err)
+	pl, err = r.MatchRegexp([]byte("fruit"), []byte(".*apple"), mustCompileRegexp(".*apple"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+	docs, err := r.Docs(pl)
+	require.NoError(t, err)
+	require.True(t, docs.Next())
+	require.Equal(t, testDocuments[2], docs.Current())
+	require.False(t, docs.Next())
+	require.NoError(t, docs.Close())
+	require.NoError(t, r.Close())
+	require.NoError(t, segment.Close())
+}
+
+func TestSegmentInsertBatchUpsert(t *testing.T) {
+	segment, err := NewSegment(0, NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err := segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	before, err := segment.Reader()
+	require.NoError(t, err)
+
+	updated := doc.Document{
+		ID: []byte("42"),
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("pineapple"),
+			},
+			doc.Field{
+				Name:  []byte("color"),
+				Value: []byte("green"),
+			},
+		},
+	}
+
+	// Without the upsert option the existing document is left unchanged.
+	require.NoError(t, segment.InsertBatch(index.NewBatch([]doc.Document{updated})))
+	require.Equal(t, int64(3), segment.Size())
+
+	require.NoError(t, segment.InsertBatch(index.NewBatch([]doc.Document{updated}, index.Upsert())))
+	ok, err := segment.ContainsID(updated.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	after, err := segment.Reader()
+	require.NoError(t, err)
+
+	// The reader created before the update still sees the old version of the document.
+	pl, err := before.MatchTerm(doc.IDReservedFieldName, updated.ID)
+	require.NoError(t, err)
+	iter, err := before.Docs(pl)
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	require.Equal(t, testDocuments[2], iter.Current())
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Close())
+
+	// The reader created after the update only sees the new version of the document.
+	pl, err = after.MatchTerm(doc.IDReservedFieldName, updated.ID)
+	require.NoError(t, err)
+	iter, err = after.Docs(pl)
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	require.Equal(t, updated, iter.Current())
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Close())
+
+	pl, err = after.MatchTerm([]byte("color"), []byte("yellow"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	require.NoError(t, before.Close())
+	require.NoError(t, after.Close())
+	require.NoError(t, segment.Close())
+}
+
 func TestSegmentInsertBatchPartialErrorAlreadyIndexing(t *testing.T) {
 	b1 := index.NewBatch(
 		[]doc.Document{
@@ -688,6 +856,44 @@ func TestSegmentReaderMatchExact(t *testing.T) {
 	require.NoError(t, segment.Close())
 }
 
+func TestSegmentReaderMatchTermIgnoresInsertsAfterReader(t *testing.T) {
+	apple := doc.Document{
+		Fields: []doc.Field{
+			doc.Field{
+				Name:  []byte("fruit"),
+				Value: []byte("apple"),
+			},
+		},
+	}
+
+	segment, err := NewSegment(0, NewOptions())
+	require.NoError(t, err)
+
+	_, err = segment.Insert(apple)
+	require.NoError(t, err)
+
+	r, err := segment.Reader()
+	require.NoError(t, err)
+
+	pl, err := r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	// Documents inserted after the reader was created are added to the segment's postings
+	// list for the term but must not be counted in the list the reader returned, or in
+	// any list it returns later.
+	_, err = segment.Insert(apple)
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	pl, err = r.MatchTerm([]byte("fruit"), []byte("apple"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pl.Len())
+
+	require.NoError(t, r.Close())
+	require.NoError(t, segment.Close())
+}
+
 func TestSegmentSealLifecycle(t *testing.T) {
 	segment, err := NewSegment(0, NewOptions())
 	require.NoError(t, err)
@@ -795,7 +1001,7 @@ func TestSegmentReaderMatchRegex(t *testing.T) {
 	require.NoError(t, err)
 
 	field, regexp := []byte("fruit"), []byte(".*ple")
-	compiled := re.MustCompile(string(regexp))
+	compiled := mustCompileRegexp(string(regexp))
 	pl, err := r.MatchRegexp(field, regexp, compiled)
 	require.NoError(t, err)
 
@@ -820,6 +1026,34 @@ func TestSegmentReaderMatchRegex(t *testing.T) {
 	require.NoError(t, segment.Close())
 }
 
+func TestSegmentReaderMatchRegexWithoutCompiled(t *testing.T) {
+	segment, err := NewSegment(0, NewOptions())
+	require.NoError(t, err)
+
+	for _, d := range testDocuments {
+		_, err = segment.Insert(d)
+		require.NoError(t, err)
+	}
+
+	r, err := segment.Reader()
+	require.NoError(t, err)
+
+	// The regular expression must match the whole term so "pineapple" is not matched.
+	pl, err := r.MatchRegexp([]byte("fruit"), []byte("apple"), nil)
+	require.NoError(t, err)
+
+	iter, err := r.Docs(pl)
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	require.True(t, compareDocs(testDocuments[1], iter.Current()))
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+
+	require.NoError(t, r.Close())
+	require.NoError(t, segment.Close())
+}
+
 func testDocument(t *testing.T, d doc.Document, r index.Reader) {
 	for _, f := range d.Fields {
 		name, value := f.Name, f.Value
@@ -853,3 +1087,12 @@ func compareDocs(expected, actual doc.Document) bool {
 	}
 	return expected.Equal(actual)
 }
+
+// mustCompileRegexp compiles a regular expression as the queries do, see index.CompileRegexp.
+func mustCompileRegexp(r string) *re.Regexp {
+	compiled, err := index.CompileRegexp([]byte(r))
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
 	"github.com/m3db/m3ninx/postings"
 )
 
@@ -64,6 +65,27 @@ func (d *termsDict) MatchTerm(field, term []byte) postings.List {
 	return pl
 }
 
+func (d *termsDict) MatchTerms(field []byte, terms [][]byte) (postings.List, error) {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+
+	pl := d.opts.PostingsListPool().Get()
+	for _, term := range terms {
+		termPl, ok := postingsMap.Get(term)
+		if !ok {
+			continue
+		}
+		if err := pl.Union(termPl); err != nil {
+			return nil, err
+		}
+	}
+	return pl, nil
+}
+
 func (d *termsDict) Fields() [][]byte {
 	d.fields.RLock()
 	defer d.fields.RUnlock()
@@ -84,6 +106,16 @@ func (d *termsDict) Terms(field []byte) [][]byte {
 	return values.Keys()
 }
 
+func (d *termsDict) TermsIterator(field []byte) index.TermsIterator {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return newTermsIterator(nil, nil)
+	}
+	return newTermsIterator(postingsMap.SortedKeys(), postingsMap)
+}
+
 func (d *termsDict) matchTerm(field, term []byte) (postings.List, bool) {
 	d.fields.RLock()
 	postingsMap, ok := d.fields.Get(field)
@@ -101,18 +133,75 @@ func (d *termsDict) matchTerm(field, term []byte) (postings.List, bool) {
 func (d *termsDict) MatchRegexp(
 	field, regexp []byte,
 	compiled *re.Regexp,
-) postings.List {
+) (postings.List, error) {
 	d.fields.RLock()
 	postingsMap, ok := d.fields.Get(field)
 	d.fields.RUnlock()
 	if !ok {
-		return d.opts.PostingsListPool().Get()
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	pl, ok, err := postingsMap.GetRegex(compiled)
+	if err != nil {
+		return nil, err
 	}
-	pl, ok := postingsMap.GetRegex(compiled)
 	if !ok {
-		return d.opts.PostingsListPool().Get()
+		return d.opts.PostingsListPool().Get(), nil
 	}
-	return pl
+	return pl, nil
+}
+
+func (d *termsDict) MatchField(field []byte) (postings.List, error) {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	pl, ok, err := postingsMap.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	return pl, nil
+}
+
+func (d *termsDict) MatchPrefix(field, prefix []byte) (postings.List, error) {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	pl, ok, err := postingsMap.GetPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	return pl, nil
+}
+
+func (d *termsDict) MatchTermRange(
+	field, min, max []byte,
+	minInclusive, maxInclusive bool,
+) (postings.List, error) {
+	d.fields.RLock()
+	postingsMap, ok := d.fields.Get(field)
+	d.fields.RUnlock()
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	pl, ok, err := postingsMap.GetRange(min, max, minInclusive, maxInclusive)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return d.opts.PostingsListPool().Get(), nil
+	}
+	return pl, nil
 }
 
 func (d *termsDict) getOrAddName(name []byte) *concurrentPostingsMap {
@@ -21,7 +21,6 @@
 package mem
 
 import (
-	"regexp"
 	"testing"
 
 	"github.com/m3db/m3ninx/doc"
@@ -32,7 +31,7 @@ import (
 var (
 	benchTermsDictField    = []byte("__name__")
 	benchTermsDictRegexp   = []byte("node_netstat_Tcp_.*")
-	benchTermsDictCompiled = regexp.MustCompile(string(benchTermsDictRegexp))
+	benchTermsDictCompiled = mustCompileRegexp(string(benchTermsDictRegexp))
 )
 
 func BenchmarkTermsDict(b *testing.B) {
@@ -235,7 +235,10 @@ func (t *termsDictionaryTestSuite) TestMatchRegex() {
 
 				t.termsDict.Insert(f, id)
 
-				pl := t.termsDict.MatchRegexp(f.Name, []byte(regexp), compiled)
+				pl, err := t.termsDict.MatchRegexp(f.Name, []byte(regexp), compiled)
+				if err != nil {
+					return false, err
+				}
 				if pl == nil {
 					return false, fmt.Errorf("postings list of documents matching query should not be nil")
 				}
@@ -263,7 +266,10 @@ func (t *termsDictionaryTestSuite) TestMatchRegexNoResults() {
 					regexp   = input.regexp
 					compiled = input.compiled
 				)
-				pl := t.termsDict.MatchRegexp(f.Name, []byte(regexp), compiled)
+				pl, err := t.termsDict.MatchRegexp(f.Name, []byte(regexp), compiled)
+				if err != nil {
+					return false, err
+				}
 				if pl == nil {
 					return false, fmt.Errorf("postings list returned should not be nil")
 				}
@@ -280,6 +286,64 @@ func (t *termsDictionaryTestSuite) TestMatchRegexNoResults() {
 	props.TestingRun(t.T())
 }
 
+func (t *termsDictionaryTestSuite) TestMatchPrefix() {
+	props := getProperties()
+	props.Property(
+		"The dictionary should support prefix queries",
+		prop.ForAll(
+			func(f doc.Field, id postings.ID) (bool, error) {
+				t.termsDict.Insert(f, id)
+
+				prefix := f.Value[:len(f.Value)/2]
+				pl, err := t.termsDict.MatchPrefix(f.Name, prefix)
+				if err != nil {
+					return false, err
+				}
+				if pl == nil {
+					return false, fmt.Errorf("postings list of documents matching query should not be nil")
+				}
+				if !pl.Contains(id) {
+					return false, fmt.Errorf("id of new document '%v' is not in list of matching documents", id)
+				}
+
+				return true, nil
+			},
+			genField(),
+			genDocID(),
+		))
+
+	props.TestingRun(t.T())
+}
+
+func (t *termsDictionaryTestSuite) TestMatchTerms() {
+	props := getProperties()
+	props.Property(
+		"The dictionary should support terms queries",
+		prop.ForAll(
+			func(f doc.Field, id postings.ID) (bool, error) {
+				t.termsDict.Insert(f, id)
+
+				terms := [][]byte{f.Value, append(append([]byte(nil), f.Value...), 0xff)}
+				pl, err := t.termsDict.MatchTerms(f.Name, terms)
+				if err != nil {
+					return false, err
+				}
+				if pl == nil {
+					return false, fmt.Errorf("postings list of documents matching query should not be nil")
+				}
+				if !pl.Contains(id) {
+					return false, fmt.Errorf("id of new document '%v' is not in list of matching documents", id)
+				}
+
+				return true, nil
+			},
+			genField(),
+			genDocID(),
+		))
+
+	props.TestingRun(t.T())
+}
+
 func TestTermsDictionary(t *testing.T) {
 	opts := NewOptions()
 	suite.Run(t, &termsDictionaryTestSuite{
@@ -351,7 +415,7 @@ func fieldFromRegexp(regexp string) gopter.Gen {
 		return fieldAndRegexp{
 			field:    f,
 			regexp:   regexp,
-			compiled: re.MustCompile(regexp),
+			compiled: mustCompileRegexp(regexp),
 		}
 	})
 }
@@ -0,0 +1,88 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package mem
+
+import (
+	"errors"
+
+	"github.com/m3db/m3ninx/postings"
+)
+
+var (
+	errTermsIteratorClosed = errors.New("terms iterator is closed")
+)
+
+// termsIterator is an iterator over a snapshot of the terms of a field.
+type termsIterator struct {
+	terms       [][]byte
+	postingsMap *concurrentPostingsMap
+
+	idx      int
+	currTerm []byte
+	currPl   postings.List
+	closed   bool
+}
+
+func newTermsIterator(terms [][]byte, m *concurrentPostingsMap) *termsIterator {
+	return &termsIterator{
+		terms:       terms,
+		postingsMap: m,
+		idx:         -1,
+	}
+}
+
+func (it *termsIterator) Next() bool {
+	if it.closed {
+		return false
+	}
+
+	for it.idx < len(it.terms)-1 {
+		it.idx++
+		term := it.terms[it.idx]
+		pl, ok := it.postingsMap.Get(term)
+		if !ok {
+			continue
+		}
+		it.currTerm, it.currPl = term, pl
+		return true
+	}
+
+	it.currTerm, it.currPl = nil, nil
+	return false
+}
+
+func (it *termsIterator) Current() ([]byte, postings.List) {
+	return it.currTerm, it.currPl
+}
+
+func (it *termsIterator) Err() error {
+	return nil
+}
+
+func (it *termsIterator) Close() error {
+	if it.closed {
+		return errTermsIteratorClosed
+	}
+	it.closed = true
+	it.terms = nil
+	it.currTerm, it.currPl = nil, nil
+	return nil
+}
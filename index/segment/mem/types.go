@@ -24,6 +24,7 @@ import (
 	re "regexp"
 
 	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
 	"github.com/m3db/m3ninx/postings"
 )
 
@@ -40,15 +41,36 @@ type termsDictionary interface {
 	// given field term exactly.
 	MatchTerm(field, term []byte) postings.List
 
+	// MatchTerms returns the postings list corresponding to documents which match any of
+	// the given field terms exactly.
+	MatchTerms(field []byte, terms [][]byte) (postings.List, error)
+
 	// MatchRegexp returns the postings list corresponding to documents which match the
 	// given egular expression.
-	MatchRegexp(field, regexp []byte, compiled *re.Regexp) postings.List
+	MatchRegexp(field, regexp []byte, compiled *re.Regexp) (postings.List, error)
+
+	// MatchField returns the postings list corresponding to documents which have the given
+	// field.
+	MatchField(field []byte) (postings.List, error)
+
+	// MatchPrefix returns the postings list corresponding to documents which match the
+	// given field and have a term beginning with the given prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
+	// MatchTermRange returns the postings list corresponding to documents which match the
+	// given field and have a term within the given range. An empty bound leaves that side
+	// of the range open.
+	MatchTermRange(field, min, max []byte, minInclusive, maxInclusive bool) (postings.List, error)
 
 	// Fields returns the list of known fields.
 	Fields() [][]byte
 
 	// Terms returns the list of known terms values for the given field.
 	Terms(field []byte) [][]byte
+
+	// TermsIterator returns an iterator over the terms of the given field in ascending
+	// order along with their postings lists.
+	TermsIterator(field []byte) index.TermsIterator
 }
 
 // ReadableSegment is an internal interface for reading from a segment.
@@ -61,9 +83,31 @@ type ReadableSegment interface {
 	// matchTerm returns the postings list of documents which match the given term exactly.
 	matchTerm(field, term []byte) (postings.List, error)
 
+	// matchTerms returns the postings list of documents which match any of the given terms
+	// exactly.
+	matchTerms(field []byte, terms [][]byte) (postings.List, error)
+
 	// matchRegexp returns the postings list of documents which match the given regular expression.
 	matchRegexp(name, regexp []byte, compiled *re.Regexp) (postings.List, error)
 
+	// matchField returns the postings list of documents which have the given field.
+	matchField(field []byte) (postings.List, error)
+
+	// matchPrefix returns the postings list of documents which have a term beginning with
+	// the given prefix.
+	matchPrefix(field, prefix []byte) (postings.List, error)
+
+	// matchTermRange returns the postings list of documents which have a term within the
+	// given range.
+	matchTermRange(field, min, max []byte, minInclusive, maxInclusive bool) (postings.List, error)
+
 	// getDoc returns the document associated with the given ID.
 	getDoc(id postings.ID) (doc.Document, error)
+
+	// fields returns the fields known to the segment.
+	fields() ([][]byte, error)
+
+	// fieldTerms returns an iterator over the terms of the given field and their postings
+	// lists.
+	fieldTerms(field []byte) (index.TermsIterator, error)
 }
@@ -29,6 +29,7 @@ import (
 
 	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 
 	"github.com/golang/mock/gomock"
 )
@@ -81,6 +82,18 @@ func (mr *MockSegmentMockRecorder) ContainsID(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsID", reflect.TypeOf((*MockSegment)(nil).ContainsID), arg0)
 }
 
+// DeletePostings mocks base method
+func (m *MockSegment) DeletePostings(arg0 postings.List) error {
+	ret := m.ctrl.Call(m, "DeletePostings", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostings indicates an expected call of DeletePostings
+func (mr *MockSegmentMockRecorder) DeletePostings(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostings", reflect.TypeOf((*MockSegment)(nil).DeletePostings), arg0)
+}
+
 // Fields mocks base method
 func (m *MockSegment) Fields() ([][]byte, error) {
 	ret := m.ctrl.Call(m, "Fields")
@@ -180,6 +193,30 @@ func (mr *MockMutableSegmentMockRecorder) ContainsID(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainsID", reflect.TypeOf((*MockMutableSegment)(nil).ContainsID), arg0)
 }
 
+// Delete mocks base method
+func (m *MockMutableSegment) Delete(arg0 []byte) error {
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockMutableSegmentMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMutableSegment)(nil).Delete), arg0)
+}
+
+// DeletePostings mocks base method
+func (m *MockMutableSegment) DeletePostings(arg0 postings.List) error {
+	ret := m.ctrl.Call(m, "DeletePostings", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostings indicates an expected call of DeletePostings
+func (mr *MockMutableSegmentMockRecorder) DeletePostings(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostings", reflect.TypeOf((*MockMutableSegment)(nil).DeletePostings), arg0)
+}
+
 // Fields mocks base method
 func (m *MockMutableSegment) Fields() ([][]byte, error) {
 	ret := m.ctrl.Call(m, "Fields")
@@ -24,6 +24,7 @@ import (
 	"errors"
 
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 )
 
 var (
@@ -50,6 +51,11 @@ type Segment interface {
 	// Terms returns the list of known terms values for the given field.
 	Terms(field []byte) ([][]byte, error)
 
+	// DeletePostings marks the documents with the given postings IDs as deleted by
+	// adding them to the segment's tombstones. Deleted documents are excluded from
+	// Readers created after the deletion and are dropped when the segment is rewritten.
+	DeletePostings(pl postings.List) error
+
 	// Close closes the segment and releases any internal resources.
 	Close() error
 }
@@ -30,8 +30,14 @@ import (
 	xerrors "github.com/m3db/m3x/errors"
 )
 
-// ErrDocNotFound is the error returned when there is no document for a given postings ID.
-var ErrDocNotFound = errors.New("no document with given postings ID")
+var (
+	// ErrDocNotFound is the error returned when there is no document for a given postings ID.
+	ErrDocNotFound = errors.New("no document with given postings ID")
+
+	// ErrDocFrequencyUnavailable is the error returned when the document frequency of a term
+	// cannot be determined without retrieving its postings list.
+	ErrDocFrequencyUnavailable = errors.New("document frequency is unavailable")
+)
 
 // Index is a collection of searchable documents.
 type Index interface {
@@ -55,6 +61,11 @@ type Writer interface {
 	// partial updates and any errors are encountered on individual documents then a
 	// BatchPartialError is returned.
 	InsertBatch(b Batch) error
+
+	// Delete deletes the document with the given ID from the index. Deleting a document
+	// which does not exist is a no-op. Readers created prior to the deletion continue to
+	// see the document.
+	Delete(id []byte) error
 }
 
 // Readable provides a point-in-time accessor to the documents in an index.
@@ -64,16 +75,49 @@ type Readable interface {
 	// MatchTerm returns a postings list over all documents which match the given term.
 	MatchTerm(field, term []byte) (postings.List, error)
 
-	// MatchRegexp returns a postings list over all documents which match the given
-	// regular expression.
+	// MatchTerms returns a postings list over all documents which match any of the given
+	// terms. The terms must be sorted in ascending order.
+	MatchTerms(field []byte, terms [][]byte) (postings.List, error)
+
+	// DocFrequency returns an estimate of the number of documents which match the given
+	// term. It is cheaper than retrieving the term's postings list but may count documents
+	// which have since been deleted. It returns ErrDocFrequencyUnavailable if the estimate
+	// would require retrieving the term's postings list.
+	DocFrequency(field, term []byte) (int, error)
+
+	// MatchRegexp returns a postings list over all documents which have a term matching
+	// the given regular expression in its entirety. If provided, the compiled regular
+	// expression must have been compiled with CompileRegexp.
 	MatchRegexp(field, regexp []byte, compiled *regexp.Regexp) (postings.List, error)
 
+	// MatchField returns a postings list over all documents which have the given field.
+	MatchField(field []byte) (postings.List, error)
+
+	// MatchPrefix returns a postings list over all documents which have a term for the
+	// given field beginning with the given prefix.
+	MatchPrefix(field, prefix []byte) (postings.List, error)
+
+	// MatchTermRange returns a postings list over all documents which have a term for the
+	// given field within the given lexicographic range. An empty bound leaves that side of
+	// the range open.
+	MatchTermRange(field, min, max []byte, minInclusive, maxInclusive bool) (postings.List, error)
+
+	// FieldNames returns the fields of the documents known to the Reader. The fields are
+	// not restricted to those of the documents visible to the Reader.
+	FieldNames() ([][]byte, error)
+
+	// FieldTerms returns an iterator over the terms of the given field in ascending order
+	// along with the postings list of the documents which contain each term. The postings
+	// lists are not restricted to the documents visible to the Reader so they should be
+	// intersected with a postings list returned by one of the Match methods.
+	FieldTerms(field []byte) (TermsIterator, error)
+
 	// MatchAll returns a postings list for all documents known to the Reader.
 	MatchAll() (postings.MutableList, error)
 
 	// Docs returns an iterator over the documents whose IDs are in the provided
 	// postings list.
-	Docs(pl postings.List) (doc.Iterator, error)
+	Docs(pl postings.List) (IDDocIterator, error)
 
 	// AllDocs returns an iterator over the documents known to the Reader.
 	AllDocs() (IDDocIterator, error)
@@ -93,6 +137,23 @@ type IDDocIterator interface {
 	PostingsID() postings.ID
 }
 
+// TermsIterator is an iterator over the terms of a field and the postings lists of the
+// documents which contain them. It is not safe for concurrent access.
+type TermsIterator interface {
+	// Next returns whether the iterator has another term.
+	Next() bool
+
+	// Current returns the current term and its postings list. The term is only valid until
+	// the next call to Next and the postings list must not be modified.
+	Current() ([]byte, postings.List)
+
+	// Err returns any errors encountered during iteration.
+	Err() error
+
+	// Close closes the iterator and releases any internal resources.
+	Close() error
+}
+
 // Reader provides a point-in-time accessor to the documents in an index.
 type Reader interface {
 	Readable
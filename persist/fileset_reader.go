@@ -0,0 +1,211 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persist
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/m3db/m3ninx/generated/proto/persistpb"
+	"github.com/m3db/m3ninx/x"
+
+	"github.com/edsrzf/mmap-go"
+)
+
+var (
+	errFileSetIncomplete    = errors.New("file set volume is incomplete, info file does not exist")
+	errSegmentFileClosed    = errors.New("segment file is closed")
+	errSegmentFileTruncated = errors.New("segment file size does not match info file")
+)
+
+type fileSetReader struct {
+	dir  string
+	info persistpb.IndexInfo
+	idx  int
+}
+
+// NewFileSetReader returns a new IndexFileSetReader which reads the segments of the volume
+// at the provided directory. The files of each segment are memory mapped so the bytes
+// returned by the segment files are not copied.
+func NewFileSetReader(dir string) (IndexFileSetReader, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, infoFileName))
+	if os.IsNotExist(err) {
+		return nil, errFileSetIncomplete
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &fileSetReader{
+		dir: dir,
+	}
+	if err := r.info.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *fileSetReader) SegmentFileSets() int {
+	return len(r.info.Segments)
+}
+
+func (r *fileSetReader) ReadSegmentFileSet() (IndexSegmentFileSet, error) {
+	if r.idx >= len(r.info.Segments) {
+		return nil, io.EOF
+	}
+
+	var (
+		idx         = r.idx
+		segment     = r.info.Segments[idx]
+		segmentType = IndexSegmentType(segment.SegmentType)
+		files       = make([]IndexSegmentFile, 0, len(segment.Files))
+		success     = false
+	)
+	r.idx++
+
+	defer func() {
+		if success {
+			return
+		}
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, info := range segment.Files {
+		fileType := IndexSegmentFileType(info.FileType)
+		path := filepath.Join(r.dir, segmentFileName(idx, segmentType, fileType))
+		f, err := openSegmentFile(path, fileType, info.NumBytes)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	success = true
+	return &segmentFileSet{
+		segmentType:  segmentType,
+		majorVersion: int(segment.MajorVersion),
+		minorVersion: int(segment.MinorVersion),
+		metadata:     segment.Metadata,
+		files:        files,
+	}, nil
+}
+
+type segmentFileSet struct {
+	segmentType  IndexSegmentType
+	majorVersion int
+	minorVersion int
+	metadata     []byte
+	files        []IndexSegmentFile
+}
+
+func (s *segmentFileSet) SegmentType() IndexSegmentType { return s.segmentType }
+func (s *segmentFileSet) MajorVersion() int             { return s.majorVersion }
+func (s *segmentFileSet) MinorVersion() int             { return s.minorVersion }
+func (s *segmentFileSet) SegmentMetadata() []byte       { return s.metadata }
+func (s *segmentFileSet) Files() []IndexSegmentFile     { return s.files }
+
+// mmapSegmentFile is an IndexSegmentFile backed by a read-only memory mapped file.
+type mmapSegmentFile struct {
+	fileType IndexSegmentFileType
+	data     mmap.MMap
+	reader   *bytes.Reader
+	closed   bool
+}
+
+func openSegmentFile(path string, fileType IndexSegmentFileType, numBytes int64) (IndexSegmentFile, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	closer := x.NewSafeCloser(fd)
+	defer closer.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.Size() != numBytes {
+		return nil, fmt.Errorf("%v: %s has %d bytes, expected %d",
+			errSegmentFileTruncated, path, stat.Size(), numBytes)
+	}
+
+	// Empty files cannot be memory mapped.
+	var data mmap.MMap
+	if numBytes > 0 {
+		data, err = mmap.Map(fd, mmap.RDONLY, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The mapping remains valid once the file descriptor is closed.
+	if err := closer.Close(); err != nil {
+		if data != nil {
+			data.Unmap()
+		}
+		return nil, err
+	}
+
+	return &mmapSegmentFile{
+		fileType: fileType,
+		data:     data,
+		reader:   bytes.NewReader(data),
+	}, nil
+}
+
+func (f *mmapSegmentFile) SegmentFileType() IndexSegmentFileType {
+	return f.fileType
+}
+
+func (f *mmapSegmentFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, errSegmentFileClosed
+	}
+	return f.reader.Read(p)
+}
+
+func (f *mmapSegmentFile) Bytes() ([]byte, error) {
+	if f.closed {
+		return nil, errSegmentFileClosed
+	}
+	if f.data == nil {
+		return []byte{}, nil
+	}
+	return f.data, nil
+}
+
+func (f *mmapSegmentFile) Close() error {
+	if f.closed {
+		return errSegmentFileClosed
+	}
+	f.closed = true
+	if f.data == nil {
+		return nil
+	}
+	return f.data.Unmap()
+}
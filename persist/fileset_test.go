@@ -0,0 +1,239 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persist
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index/segment/fs"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+	xtest "github.com/m3db/m3x/test"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var testFileSetDocs = [][]doc.Document{
+	{
+		doc.Document{
+			ID: []byte("apple"),
+			Fields: []doc.Field{
+				doc.Field{Name: []byte("fruit"), Value: []byte("apple")},
+				doc.Field{Name: []byte("color"), Value: []byte("red")},
+			},
+		},
+		doc.Document{
+			ID: []byte("banana"),
+			Fields: []doc.Field{
+				doc.Field{Name: []byte("fruit"), Value: []byte("banana")},
+				doc.Field{Name: []byte("color"), Value: []byte("yellow")},
+			},
+		},
+	},
+	{
+		doc.Document{
+			ID: []byte("lemon"),
+			Fields: []doc.Field{
+				doc.Field{Name: []byte("fruit"), Value: []byte("lemon")},
+				doc.Field{Name: []byte("color"), Value: []byte("yellow")},
+			},
+		},
+	},
+}
+
+func TestFileSetWriteRead(t *testing.T) {
+	dir, cleanup := newTestVolumeDir(t)
+	defer cleanup()
+
+	w, err := NewFileSetWriter(dir)
+	require.NoError(t, err)
+	for _, docs := range testFileSetDocs {
+		require.NoError(t, w.WriteSegmentFileSet(newTestSegmentFileSetWriter(t, docs)))
+	}
+
+	// The volume is not readable until the writer has been closed.
+	_, err = NewFileSetReader(dir)
+	require.Equal(t, errFileSetIncomplete, err)
+	require.NoError(t, w.Close())
+	require.Error(t, w.WriteSegmentFileSet(newTestSegmentFileSetWriter(t, testFileSetDocs[0])))
+
+	// No temporary files are left behind.
+	tempFiles, err := filepath.Glob(filepath.Join(dir, "*"+tempFileSuffix))
+	require.NoError(t, err)
+	require.Empty(t, tempFiles)
+
+	r, err := NewFileSetReader(dir)
+	require.NoError(t, err)
+	require.Equal(t, len(testFileSetDocs), r.SegmentFileSets())
+
+	for _, docs := range testFileSetDocs {
+		fileset, err := r.ReadSegmentFileSet()
+		require.NoError(t, err)
+		require.Equal(t, FSTIndexSegmentType, fileset.SegmentType())
+		require.Equal(t, fs.MajorVersion, fileset.MajorVersion())
+		require.Equal(t, fs.NewWriter().MinorVersion(), fileset.MinorVersion())
+		require.Len(t, fileset.Files(), 5)
+
+		seg, err := NewSegment(fileset, fs.NewSegmentOpts{
+			PostingsListPool: postings.NewPool(nil, roaring.NewPostingsList),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(len(docs)), seg.Size())
+
+		reader, err := seg.Reader()
+		require.NoError(t, err)
+		for _, d := range docs {
+			pl, err := reader.MatchTerm([]byte("fruit"), d.Fields[0].Value)
+			require.NoError(t, err)
+			require.Equal(t, 1, pl.Len())
+
+			iter, err := reader.Docs(pl)
+			require.NoError(t, err)
+			require.True(t, iter.Next())
+			require.Equal(t, d, iter.Current())
+			require.False(t, iter.Next())
+			require.NoError(t, iter.Close())
+		}
+		require.NoError(t, reader.Close())
+		require.NoError(t, seg.Close())
+	}
+
+	_, err = r.ReadSegmentFileSet()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestFileSetWriterVolumeExists(t *testing.T) {
+	dir, cleanup := newTestVolumeDir(t)
+	defer cleanup()
+
+	w, err := NewFileSetWriter(dir)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = NewFileSetWriter(dir)
+	require.Equal(t, errFileSetExists, err)
+}
+
+func TestFileSetWriterWriteFileError(t *testing.T) {
+	ctrl := gomock.NewController(xtest.Reporter{t})
+	defer ctrl.Finish()
+
+	dir, cleanup := newTestVolumeDir(t)
+	defer cleanup()
+
+	errWrite := errors.New("write error")
+	fileset := NewMockIndexSegmentFileSetWriter(ctrl)
+	fileset.EXPECT().SegmentType().Return(FSTIndexSegmentType)
+	fileset.EXPECT().MajorVersion().Return(fs.MajorVersion)
+	fileset.EXPECT().MinorVersion().Return(fs.MinorVersion)
+	fileset.EXPECT().SegmentMetadata().Return(nil)
+	fileset.EXPECT().Files().Return([]IndexSegmentFileType{DocumentDataIndexSegmentFileType})
+	fileset.EXPECT().WriteFile(DocumentDataIndexSegmentFileType, gomock.Any()).Return(errWrite)
+
+	w, err := NewFileSetWriter(dir)
+	require.NoError(t, err)
+	require.Equal(t, errWrite, w.WriteSegmentFileSet(fileset))
+
+	// Neither the segment file nor its temporary file are left behind.
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestFileSetReaderTruncatedFile(t *testing.T) {
+	dir, cleanup := newTestVolumeDir(t)
+	defer cleanup()
+
+	w, err := NewFileSetWriter(dir)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteSegmentFileSet(newTestSegmentFileSetWriter(t, testFileSetDocs[0])))
+	require.NoError(t, w.Close())
+
+	path := filepath.Join(dir, segmentFileName(0, FSTIndexSegmentType, PostingsIndexSegmentFileType))
+	require.NoError(t, os.Truncate(path, 1))
+
+	r, err := NewFileSetReader(dir)
+	require.NoError(t, err)
+	_, err = r.ReadSegmentFileSet()
+	require.Error(t, err)
+}
+
+func TestSegmentFileClosed(t *testing.T) {
+	dir, cleanup := newTestVolumeDir(t)
+	defer cleanup()
+
+	w, err := NewFileSetWriter(dir)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteSegmentFileSet(newTestSegmentFileSetWriter(t, testFileSetDocs[0])))
+	require.NoError(t, w.Close())
+
+	r, err := NewFileSetReader(dir)
+	require.NoError(t, err)
+	fileset, err := r.ReadSegmentFileSet()
+	require.NoError(t, err)
+
+	for _, f := range fileset.Files() {
+		b, err := f.Bytes()
+		require.NoError(t, err)
+
+		// Reading the file returns the same bytes.
+		read, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, len(b), len(read))
+
+		require.NoError(t, f.Close())
+		_, err = f.Bytes()
+		require.Equal(t, errSegmentFileClosed, err)
+		require.Equal(t, errSegmentFileClosed, f.Close())
+	}
+}
+
+// newTestVolumeDir returns a volume directory which does not exist yet along with a
+// function to remove it.
+func newTestVolumeDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "fileset")
+	require.NoError(t, err)
+	return filepath.Join(dir, "volume"), func() { os.RemoveAll(dir) }
+}
+
+func newTestSegmentFileSetWriter(t *testing.T, docs []doc.Document) IndexSegmentFileSetWriter {
+	seg, err := mem.NewSegment(0, mem.NewOptions())
+	require.NoError(t, err)
+	for _, d := range docs {
+		_, err := seg.Insert(d)
+		require.NoError(t, err)
+	}
+	_, err = seg.Seal()
+	require.NoError(t, err)
+
+	w, err := NewMutableSegmentFileSetWriter()
+	require.NoError(t, err)
+	require.NoError(t, w.Reset(seg))
+	return w
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package persist
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/m3db/m3ninx/generated/proto/persistpb"
+)
+
+const (
+	infoFileName     = "info.db"
+	tempFileSuffix   = ".tmp"
+	volumeDirPerm    = 0755
+	volumeFilePerm   = 0644
+	segmentFilesBase = "segment"
+)
+
+var (
+	errFileSetWriterClosed = errors.New("file set writer is closed")
+	errFileSetExists       = errors.New("file set volume already exists")
+)
+
+type fileSetWriter struct {
+	dir    string
+	info   persistpb.IndexInfo
+	closed bool
+}
+
+// NewFileSetWriter returns a new FileSetWriter which writes segments to the volume at the
+// provided directory, creating it if it does not exist. It returns an error if a complete
+// volume already exists in the directory.
+func NewFileSetWriter(dir string) (FileSetWriter, error) {
+	if err := os.MkdirAll(dir, volumeDirPerm); err != nil {
+		return nil, err
+	}
+
+	_, err := os.Stat(filepath.Join(dir, infoFileName))
+	if err == nil {
+		return nil, errFileSetExists
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return &fileSetWriter{
+		dir: dir,
+	}, nil
+}
+
+func (w *fileSetWriter) WriteSegmentFileSet(fileset IndexSegmentFileSetWriter) error {
+	if w.closed {
+		return errFileSetWriterClosed
+	}
+
+	segmentType := fileset.SegmentType()
+	if err := segmentType.Validate(); err != nil {
+		return err
+	}
+
+	var (
+		idx     = len(w.info.Segments)
+		segment = &persistpb.SegmentInfo{
+			SegmentType:  string(segmentType),
+			MajorVersion: int64(fileset.MajorVersion()),
+			MinorVersion: int64(fileset.MinorVersion()),
+			Metadata:     fileset.SegmentMetadata(),
+		}
+	)
+	for _, fileType := range fileset.Files() {
+		if err := fileType.Validate(); err != nil {
+			return err
+		}
+
+		path := filepath.Join(w.dir, segmentFileName(idx, segmentType, fileType))
+		n, err := writeFileAtomic(path, func(iow io.Writer) error {
+			return fileset.WriteFile(fileType, iow)
+		})
+		if err != nil {
+			return err
+		}
+
+		segment.Files = append(segment.Files, &persistpb.SegmentFileInfo{
+			FileType: string(fileType),
+			NumBytes: n,
+		})
+	}
+
+	// Ensure the renames of the segment files are durable.
+	if err := syncDir(w.dir); err != nil {
+		return err
+	}
+
+	w.info.Segments = append(w.info.Segments, segment)
+	return nil
+}
+
+func (w *fileSetWriter) Close() error {
+	if w.closed {
+		return errFileSetWriterClosed
+	}
+	w.closed = true
+
+	data, err := w.info.Marshal()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(w.dir, infoFileName)
+	_, err = writeFileAtomic(path, func(iow io.Writer) error {
+		_, err := iow.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return syncDir(w.dir)
+}
+
+// segmentFileName returns the name of a file of the segment at the provided index within a
+// volume. The segment and file types cannot contain "-" so it is used as the separator.
+func segmentFileName(idx int, segmentType IndexSegmentType, fileType IndexSegmentFileType) string {
+	return fmt.Sprintf("%s-%d-%s-%s.db", segmentFilesBase, idx, segmentType, fileType)
+}
+
+// writeFileAtomic writes a file by writing to a temporary file which is synced to disk and
+// then renamed to the provided path so that the file is either complete or absent. It
+// returns the number of bytes written.
+func writeFileAtomic(path string, write func(io.Writer) error) (int64, error) {
+	tempPath := path + tempFileSuffix
+	fd, err := os.OpenFile(tempPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, volumeFilePerm)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		counter = &countingWriter{w: fd}
+		buf     = bufio.NewWriter(counter)
+	)
+	err = write(buf)
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err == nil {
+		err = fd.Sync()
+	}
+	if closeErr := fd.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return 0, err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		return 0, err
+	}
+	return counter.n, nil
+}
+
+func syncDir(dir string) error {
+	fd, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := fd.Sync(); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -68,6 +68,53 @@ func (mr *MockIndexFileSetWriterMockRecorder) WriteSegmentFileSet(segmentFileSet
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteSegmentFileSet", reflect.TypeOf((*MockIndexFileSetWriter)(nil).WriteSegmentFileSet), segmentFileSet)
 }
 
+// MockFileSetWriter is a mock of FileSetWriter interface
+type MockFileSetWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileSetWriterMockRecorder
+}
+
+// MockFileSetWriterMockRecorder is the mock recorder for MockFileSetWriter
+type MockFileSetWriterMockRecorder struct {
+	mock *MockFileSetWriter
+}
+
+// NewMockFileSetWriter creates a new mock instance
+func NewMockFileSetWriter(ctrl *gomock.Controller) *MockFileSetWriter {
+	mock := &MockFileSetWriter{ctrl: ctrl}
+	mock.recorder = &MockFileSetWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFileSetWriter) EXPECT() *MockFileSetWriterMockRecorder {
+	return m.recorder
+}
+
+// WriteSegmentFileSet mocks base method
+func (m *MockFileSetWriter) WriteSegmentFileSet(segmentFileSet IndexSegmentFileSetWriter) error {
+	ret := m.ctrl.Call(m, "WriteSegmentFileSet", segmentFileSet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteSegmentFileSet indicates an expected call of WriteSegmentFileSet
+func (mr *MockFileSetWriterMockRecorder) WriteSegmentFileSet(segmentFileSet interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteSegmentFileSet", reflect.TypeOf((*MockFileSetWriter)(nil).WriteSegmentFileSet), segmentFileSet)
+}
+
+// Close mocks base method
+func (m *MockFileSetWriter) Close() error {
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockFileSetWriterMockRecorder) Close() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFileSetWriter)(nil).Close))
+}
+
 // MockIndexSegmentFileSetWriter is a mock of IndexSegmentFileSetWriter interface
 type MockIndexSegmentFileSetWriter struct {
 	ctrl     *gomock.Controller
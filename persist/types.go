@@ -43,6 +43,16 @@ type IndexFileSetWriter interface {
 	WriteSegmentFileSet(segmentFileSet IndexSegmentFileSetWriter) error
 }
 
+// FileSetWriter is an IndexFileSetWriter which writes segments to a volume directory on
+// the local filesystem.
+type FileSetWriter interface {
+	IndexFileSetWriter
+
+	// Close completes the volume by writing out its info file. The segments written to
+	// the volume are only visible to readers once it has been closed.
+	Close() error
+}
+
 // IndexSegmentFileSetWriter is an index segment file set writer.
 type IndexSegmentFileSetWriter interface {
 	SegmentType() IndexSegmentType
@@ -57,7 +57,7 @@ func (w *writer) MajorVersion() int {
 }
 
 func (w *writer) MinorVersion() int {
-	return fs.MinorVersion
+	return w.fsWriter.MinorVersion()
 }
 
 func (w *writer) SegmentMetadata() []byte {
@@ -176,6 +176,20 @@ func (d *postingsList) Len() int {
 	return int(l)
 }
 
+// SizeInBytes returns an estimate of the number of bytes used by the provided postings
+// list. It returns false if the postings list is not backed by a Roaring Bitmap.
+func SizeInBytes(pl postings.List) (uint64, bool) {
+	d, ok := pl.(*postingsList)
+	if !ok {
+		return 0, false
+	}
+
+	d.RLock()
+	size := d.bitmap.GetSizeInBytes()
+	d.RUnlock()
+	return size, true
+}
+
 func (d *postingsList) Iterator() postings.Iterator {
 	return &roaringIterator{
 		iter: d.bitmap.Iterator(),
@@ -94,6 +94,20 @@ func TestRoaringPostingsListClone(t *testing.T) {
 	require.Equal(t, 1, d.Len())
 }
 
+func TestRoaringPostingsListSizeInBytes(t *testing.T) {
+	d := NewPostingsList()
+	empty, ok := SizeInBytes(d)
+	require.True(t, ok)
+
+	d.AddRange(0, 1000)
+	size, ok := SizeInBytes(d)
+	require.True(t, ok)
+	require.True(t, size > empty)
+
+	_, ok = SizeInBytes(postings.NewMockList(gomock.NewController(t)))
+	require.False(t, ok)
+}
+
 func TestRoaringPostingsListIntersect(t *testing.T) {
 	d := NewPostingsList()
 	d.Insert(1)
@@ -0,0 +1,241 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search"
+)
+
+func (e *executor) AggregateTerms(
+	q search.Query,
+	field []byte,
+	opts search.AggregateOptions,
+) (search.AggregateResults, error) {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed {
+		return search.AggregateResults{}, errExecutorClosed
+	}
+
+	var (
+		counts   = make(map[string]int)
+		exceeded bool
+	)
+	err := e.forEachPostingsListWithRLock(q, func(r index.Reader, pl postings.List) error {
+		if pl.IsEmpty() {
+			return nil
+		}
+
+		iter, err := r.FieldTerms(field)
+		if err != nil {
+			return err
+		}
+
+		readerExceeded, err := aggregateTerms(iter, pl, opts.TermLimit, counts)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		exceeded = exceeded || readerExceeded
+		return iter.Close()
+	})
+	if err != nil {
+		return search.AggregateResults{}, err
+	}
+
+	return newAggregateResults(counts, opts, exceeded), nil
+}
+
+func (e *executor) AggregateFields(
+	q search.Query,
+	opts search.AggregateOptions,
+) (search.AggregateResults, error) {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed {
+		return search.AggregateResults{}, errExecutorClosed
+	}
+
+	var (
+		counts   = make(map[string]int)
+		exceeded bool
+	)
+	err := e.forEachPostingsListWithRLock(q, func(r index.Reader, pl postings.List) error {
+		if pl.IsEmpty() {
+			return nil
+		}
+
+		fields, err := r.FieldNames()
+		if err != nil {
+			return err
+		}
+
+		iter := newFieldsIterator(r, fields)
+		readerExceeded, err := aggregateTerms(iter, pl, opts.TermLimit, counts)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		exceeded = exceeded || readerExceeded
+		return iter.Close()
+	})
+	if err != nil {
+		return search.AggregateResults{}, err
+	}
+
+	return newAggregateResults(counts, opts, exceeded), nil
+}
+
+// aggregateTerms adds the number of documents in pl which contain each term in iter to
+// counts. The terms are returned by the iterator in ascending order so the first limit
+// matching terms of each reader are sufficient to find the first limit matching terms
+// across all readers, along with their complete counts. It returns whether the reader
+// had more matching terms than the limit.
+func aggregateTerms(
+	iter index.TermsIterator,
+	pl postings.List,
+	limit int,
+	counts map[string]int,
+) (bool, error) {
+	var matched int
+	for iter.Next() {
+		term, termPl := iter.Current()
+		n, err := intersectionLen(termPl, pl)
+		if err != nil {
+			return false, err
+		}
+		if n == 0 {
+			continue
+		}
+
+		if limit > 0 && matched == limit {
+			return true, nil
+		}
+		matched++
+		counts[string(term)] += n
+	}
+	return false, iter.Err()
+}
+
+// intersectionLen returns the number of IDs contained in both postings lists.
+func intersectionLen(a, b postings.List) (int, error) {
+	intersection := a.Clone()
+	if err := intersection.Intersect(b); err != nil {
+		return 0, err
+	}
+	return intersection.Len(), nil
+}
+
+// newAggregateResults returns the aggregate results for the given term counts, keeping
+// only the first terms up to the limit.
+func newAggregateResults(
+	counts map[string]int,
+	opts search.AggregateOptions,
+	exceeded bool,
+) search.AggregateResults {
+	terms := make([]string, 0, len(counts))
+	for term := range counts {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	if opts.TermLimit > 0 && len(terms) > opts.TermLimit {
+		terms = terms[:opts.TermLimit]
+		exceeded = true
+	}
+
+	results := search.AggregateResults{
+		Terms:         make([]search.AggregateTerm, 0, len(terms)),
+		LimitExceeded: exceeded,
+	}
+	for _, term := range terms {
+		result := search.AggregateTerm{Term: []byte(term)}
+		if opts.Counts {
+			result.Count = counts[term]
+		}
+		results.Terms = append(results.Terms, result)
+	}
+	return results
+}
+
+// fieldsIterator is a TermsIterator over the fields of a reader in ascending order
+// along with the postings list of the documents which have each field. The reserved
+// ID field is skipped.
+type fieldsIterator struct {
+	reader index.Reader
+	fields [][]byte
+
+	idx    int
+	currPl postings.List
+	err    error
+}
+
+func newFieldsIterator(r index.Reader, fields [][]byte) *fieldsIterator {
+	filtered := fields[:0]
+	for _, f := range fields {
+		if !bytes.Equal(f, doc.IDReservedFieldName) {
+			filtered = append(filtered, f)
+		}
+	}
+	fields = filtered
+
+	sort.Slice(fields, func(i, j int) bool {
+		return bytes.Compare(fields[i], fields[j]) < 0
+	})
+	return &fieldsIterator{
+		reader: r,
+		fields: fields,
+		idx:    -1,
+	}
+}
+
+func (it *fieldsIterator) Next() bool {
+	if it.err != nil || it.idx >= len(it.fields)-1 {
+		return false
+	}
+
+	it.idx++
+	pl, err := it.reader.MatchField(it.fields[it.idx])
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.currPl = pl
+	return true
+}
+
+func (it *fieldsIterator) Current() ([]byte, postings.List) {
+	return it.fields[it.idx], it.currPl
+}
+
+func (it *fieldsIterator) Err() error {
+	return it.err
+}
+
+func (it *fieldsIterator) Close() error {
+	return nil
+}
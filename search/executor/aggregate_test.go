@@ -0,0 +1,236 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/doc"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/query"
+
+	"github.com/stretchr/testify/require"
+)
+
+var aggregateTestDocuments = [][]doc.Document{
+	[]doc.Document{
+		newAggregateTestDocument("1", "apple", "red", "us"),
+		newAggregateTestDocument("2", "banana", "yellow", "eu"),
+		newAggregateTestDocument("3", "cherry", "red", "eu"),
+	},
+	[]doc.Document{
+		newAggregateTestDocument("4", "lemon", "yellow", "us"),
+		withAggregateTestField(newAggregateTestDocument("5", "strawberry", "red", "us"), "organic", "yes"),
+		newAggregateTestDocument("6", "lime", "green", "ap"),
+	},
+}
+
+func newAggregateTestDocument(id, fruit, color, region string) doc.Document {
+	return doc.Document{
+		ID: []byte(id),
+		Fields: []doc.Field{
+			doc.Field{Name: []byte("fruit"), Value: []byte(fruit)},
+			doc.Field{Name: []byte("color"), Value: []byte(color)},
+			doc.Field{Name: []byte("region"), Value: []byte(region)},
+		},
+	}
+}
+
+func withAggregateTestField(d doc.Document, name, value string) doc.Document {
+	d.Fields = append(d.Fields, doc.Field{Name: []byte(name), Value: []byte(value)})
+	return d
+}
+
+func newAggregateTestReaders(t *testing.T) index.Readers {
+	var readers index.Readers
+	for _, docs := range aggregateTestDocuments {
+		seg, err := mem.NewSegment(0, mem.NewOptions())
+		require.NoError(t, err)
+		for _, d := range docs {
+			_, err := seg.Insert(d)
+			require.NoError(t, err)
+		}
+		r, err := seg.Reader()
+		require.NoError(t, err)
+		readers = append(readers, r)
+	}
+	return readers
+}
+
+func newAggregateTerms(termsAndCounts ...interface{}) []search.AggregateTerm {
+	var terms []search.AggregateTerm
+	for i := 0; i < len(termsAndCounts); i += 2 {
+		terms = append(terms, search.AggregateTerm{
+			Term:  []byte(termsAndCounts[i].(string)),
+			Count: termsAndCounts[i+1].(int),
+		})
+	}
+	return terms
+}
+
+func TestExecutorAggregateTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    search.Query
+		field    string
+		opts     search.AggregateOptions
+		expected search.AggregateResults
+	}{
+		{
+			name:  "all documents",
+			query: query.NewFieldQuery([]byte("fruit")),
+			field: "color",
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("green", 0, "red", 0, "yellow", 0),
+			},
+		},
+		{
+			name:  "all documents with counts",
+			query: query.NewFieldQuery([]byte("fruit")),
+			field: "color",
+			opts:  search.AggregateOptions{Counts: true},
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("green", 1, "red", 3, "yellow", 2),
+			},
+		},
+		{
+			name:  "filtered documents with counts",
+			query: query.NewTermQuery([]byte("region"), []byte("us")),
+			field: "color",
+			opts:  search.AggregateOptions{Counts: true},
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("red", 2, "yellow", 1),
+			},
+		},
+		{
+			name:  "term limit",
+			query: query.NewFieldQuery([]byte("fruit")),
+			field: "region",
+			opts:  search.AggregateOptions{Counts: true, TermLimit: 2},
+			expected: search.AggregateResults{
+				Terms:         newAggregateTerms("ap", 1, "eu", 2),
+				LimitExceeded: true,
+			},
+		},
+		{
+			name:  "term limit equal to number of terms",
+			query: query.NewFieldQuery([]byte("fruit")),
+			field: "region",
+			opts:  search.AggregateOptions{Counts: true, TermLimit: 3},
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("ap", 1, "eu", 2, "us", 3),
+			},
+		},
+		{
+			name:  "unknown field",
+			query: query.NewFieldQuery([]byte("fruit")),
+			field: "unknown",
+			expected: search.AggregateResults{
+				Terms: []search.AggregateTerm{},
+			},
+		},
+		{
+			name:  "no matching documents",
+			query: query.NewTermQuery([]byte("fruit"), []byte("kiwi")),
+			field: "color",
+			expected: search.AggregateResults{
+				Terms: []search.AggregateTerm{},
+			},
+		},
+	}
+
+	e := NewExecutor(newAggregateTestReaders(t), NewOptions())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := e.AggregateTerms(test.query, []byte(test.field), test.opts)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	require.NoError(t, e.Close())
+	_, err := e.AggregateTerms(query.NewFieldQuery([]byte("fruit")), []byte("color"), search.AggregateOptions{})
+	require.Equal(t, errExecutorClosed, err)
+}
+
+func TestExecutorAggregateFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    search.Query
+		opts     search.AggregateOptions
+		expected search.AggregateResults
+	}{
+		{
+			name:  "all documents",
+			query: query.NewFieldQuery([]byte("fruit")),
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("color", 0, "fruit", 0, "organic", 0, "region", 0),
+			},
+		},
+		{
+			name:  "all documents with counts",
+			query: query.NewFieldQuery([]byte("fruit")),
+			opts:  search.AggregateOptions{Counts: true},
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("color", 6, "fruit", 6, "organic", 1, "region", 6),
+			},
+		},
+		{
+			name:  "filtered documents with counts",
+			query: query.NewTermQuery([]byte("region"), []byte("eu")),
+			opts:  search.AggregateOptions{Counts: true},
+			expected: search.AggregateResults{
+				Terms: newAggregateTerms("color", 2, "fruit", 2, "region", 2),
+			},
+		},
+		{
+			name:  "term limit",
+			query: query.NewFieldQuery([]byte("fruit")),
+			opts:  search.AggregateOptions{TermLimit: 2},
+			expected: search.AggregateResults{
+				Terms:         newAggregateTerms("color", 0, "fruit", 0),
+				LimitExceeded: true,
+			},
+		},
+		{
+			name:  "no matching documents",
+			query: query.NewTermQuery([]byte("fruit"), []byte("kiwi")),
+			expected: search.AggregateResults{
+				Terms: []search.AggregateTerm{},
+			},
+		},
+	}
+
+	e := NewExecutor(newAggregateTestReaders(t), NewOptions())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := e.AggregateFields(test.query, test.opts)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	require.NoError(t, e.Close())
+	_, err := e.AggregateFields(query.NewFieldQuery([]byte("fruit")), search.AggregateOptions{})
+	require.Equal(t, errExecutorClosed, err)
+}
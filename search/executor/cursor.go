@@ -0,0 +1,105 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search"
+)
+
+const (
+	cursorVersion = 1
+)
+
+var (
+	// ErrCursorSnapshotChanged is the error returned when a cursor is used to resume
+	// iteration against a different snapshot than the one it was created from.
+	ErrCursorSnapshotChanged = errors.New("cursor was created from a different snapshot")
+
+	errInvalidCursor = errors.New("invalid cursor")
+)
+
+// cursor is the position after a document returned by an iterator.
+type cursor struct {
+	snapshot  []byte
+	readerIdx int
+	id        postings.ID
+}
+
+// encode returns the serialized form of the cursor. The encoding is a version byte
+// followed by the length-prefixed snapshot ID, the reader index, and the postings ID.
+func (c cursor) encode() search.Cursor {
+	var (
+		buf = make([]byte, 0, 1+3*binary.MaxVarintLen64+len(c.snapshot))
+		tmp [binary.MaxVarintLen64]byte
+	)
+	buf = append(buf, cursorVersion)
+	n := binary.PutUvarint(tmp[:], uint64(len(c.snapshot)))
+	buf = append(buf, tmp[:n]...)
+	buf = append(buf, c.snapshot...)
+	n = binary.PutUvarint(tmp[:], uint64(c.readerIdx))
+	buf = append(buf, tmp[:n]...)
+	n = binary.PutUvarint(tmp[:], uint64(c.id))
+	buf = append(buf, tmp[:n]...)
+	return search.Cursor(buf)
+}
+
+// decodeCursor decodes a serialized cursor and validates that it was created from the
+// given snapshot.
+func decodeCursor(b search.Cursor, snapshot []byte) (cursor, error) {
+	if len(b) == 0 || b[0] != cursorVersion {
+		return cursor{}, errInvalidCursor
+	}
+	b = b[1:]
+
+	size, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < size {
+		return cursor{}, errInvalidCursor
+	}
+	b = b[n:]
+	cursorSnapshot := b[:size]
+	b = b[size:]
+
+	readerIdx, n := binary.Uvarint(b)
+	if n <= 0 {
+		return cursor{}, errInvalidCursor
+	}
+	b = b[n:]
+
+	id, n := binary.Uvarint(b)
+	if n <= 0 || n != len(b) {
+		return cursor{}, errInvalidCursor
+	}
+
+	if !bytes.Equal(cursorSnapshot, snapshot) {
+		return cursor{}, ErrCursorSnapshotChanged
+	}
+
+	return cursor{
+		snapshot:  snapshot,
+		readerIdx: int(readerIdx),
+		id:        postings.ID(id),
+	}, nil
+}
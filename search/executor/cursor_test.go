@@ -0,0 +1,89 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	snapshot := []byte("snapshot")
+	tests := []cursor{
+		cursor{snapshot: snapshot, readerIdx: 0, id: 0},
+		cursor{snapshot: snapshot, readerIdx: 3, id: 1 << 20},
+	}
+
+	for _, c := range tests {
+		actual, err := decodeCursor(c.encode(), snapshot)
+		require.NoError(t, err)
+		require.Equal(t, c, actual)
+	}
+}
+
+func TestDecodeCursorErrors(t *testing.T) {
+	var (
+		snapshot = []byte("snapshot")
+		valid    = cursor{snapshot: snapshot, readerIdx: 1, id: 42}.encode()
+	)
+
+	tests := []struct {
+		name     string
+		cursor   search.Cursor
+		expected error
+	}{
+		{
+			name:     "empty cursor",
+			cursor:   search.Cursor{},
+			expected: errInvalidCursor,
+		},
+		{
+			name:     "unknown version",
+			cursor:   append(search.Cursor{cursorVersion + 1}, valid[1:]...),
+			expected: errInvalidCursor,
+		},
+		{
+			name:     "truncated cursor",
+			cursor:   valid[:len(valid)-1],
+			expected: errInvalidCursor,
+		},
+		{
+			name:     "trailing bytes",
+			cursor:   append(append(search.Cursor{}, valid...), 0),
+			expected: errInvalidCursor,
+		},
+		{
+			name:     "different snapshot",
+			cursor:   cursor{snapshot: []byte("other"), readerIdx: 1, id: 42}.encode(),
+			expected: ErrCursorSnapshotChanged,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeCursor(test.cursor, snapshot)
+			require.Equal(t, test.expected, err)
+		})
+	}
+}
@@ -24,47 +24,65 @@ import (
 	"errors"
 	"sync"
 
-	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/query"
+
+	xsync "github.com/m3db/m3x/sync"
+	"github.com/satori/go.uuid"
 )
 
 var (
 	errExecutorClosed = errors.New("executor is closed")
 )
 
-type newIteratorFn func(s search.Searcher, rs index.Readers) (doc.Iterator, error)
+type newIteratorFn func(
+	s search.Searcher,
+	rs index.Readers,
+	snapshot []byte,
+	opts search.ExecuteOptions,
+) (search.ResultIterator, error)
 
 type executor struct {
 	sync.RWMutex
 
 	newIteratorFn newIteratorFn
 	readers       index.Readers
+	snapshot      []byte
+	workerPool    xsync.WorkerPool
+	readAhead     int
+	evaluations   *evaluations
 
 	closed bool
 }
 
-// NewExecutor returns a new Executor for executing queries.
-func NewExecutor(rs index.Readers) search.Executor {
+// NewExecutor returns a new Executor for executing queries. Cursors returned by the
+// Executor's iterators can only be used to resume iteration with the same Executor.
+func NewExecutor(rs index.Readers, opts Options) search.Executor {
 	return &executor{
 		newIteratorFn: newIterator,
 		readers:       rs,
+		snapshot:      uuid.NewV4().Bytes(),
+		workerPool:    opts.WorkerPool(),
+		readAhead:     opts.ReadAhead(),
+		evaluations:   &evaluations{},
 	}
 }
 
-func (e *executor) Execute(q search.Query) (doc.Iterator, error) {
+func (e *executor) Execute(q search.Query, opts search.ExecuteOptions) (search.ResultIterator, error) {
 	e.RLock()
 	defer e.RUnlock()
 	if e.closed {
 		return nil, errExecutorClosed
 	}
 
-	s, err := q.Searcher(e.readers)
+	s, err := e.searcherWithRLock(q)
 	if err != nil {
 		return nil, err
 	}
 
-	iter, err := e.newIteratorFn(s, e.readers)
+	iter, err := e.newIteratorFn(s, e.readers, e.snapshot, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +90,70 @@ func (e *executor) Execute(q search.Query) (doc.Iterator, error) {
 	return iter, nil
 }
 
+func (e *executor) Count(q search.Query) (int, error) {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed {
+		return 0, errExecutorClosed
+	}
+
+	// The postings lists returned by the searcher only contain the documents visible to
+	// each reader so we can sum their lengths without retrieving any documents.
+	var count int
+	err := e.forEachPostingsListWithRLock(q, func(_ index.Reader, pl postings.List) error {
+		count += pl.Len()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// forEachPostingsListWithRLock calls fn with each reader and the postings list of the
+// documents matching the query in that reader. It validates that the query's searcher
+// returns exactly one postings list per reader.
+func (e *executor) forEachPostingsListWithRLock(
+	q search.Query,
+	fn func(r index.Reader, pl postings.List) error,
+) error {
+	s, err := e.searcherWithRLock(q)
+	if err != nil {
+		return err
+	}
+
+	var idx int
+	for s.Next() {
+		// Check that the Searcher hasn't returned too many postings lists.
+		if idx == len(e.readers) {
+			return errNotEnoughReaders
+		}
+		if err := fn(e.readers[idx], s.Current()); err != nil {
+			return err
+		}
+		idx++
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	// Check that the Searcher hasn't returned too few postings lists.
+	if idx != len(e.readers) {
+		return errTooManyReaders
+	}
+	return nil
+}
+
+// searcherWithRLock returns a Searcher for the simplified query over the Executor's
+// readers which evaluates the readers concurrently if the Executor has a worker pool.
+func (e *executor) searcherWithRLock(q search.Query) (search.Searcher, error) {
+	q = query.Simplify(q)
+	if e.workerPool == nil || len(e.readers) <= 1 {
+		return q.Searcher(e.readers)
+	}
+	return newParallelSearcher(q, e.readers, e.workerPool, e.evaluations, e.readAhead)
+}
+
 func (e *executor) Close() error {
 	e.Lock()
 	if e.closed {
@@ -80,5 +162,8 @@ func (e *executor) Close() error {
 	}
 	e.closed = true
 	e.Unlock()
+
+	// Wait for any readers still being evaluated on the worker pool before closing them.
+	e.evaluations.closeAndWait()
 	return e.readers.Close()
 }
@@ -25,9 +25,14 @@ import (
 
 	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/index/segment/mem"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
 	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/query"
 
 	"github.com/golang/mock/gomock"
+	xsync "github.com/m3db/m3x/sync"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,6 +44,8 @@ func (it testIterator) Next() bool            { return false }
 func (it testIterator) Current() doc.Document { return doc.Document{} }
 func (it testIterator) Err() error            { return nil }
 func (it testIterator) Close() error          { return nil }
+func (it testIterator) LimitExceeded() bool   { return false }
+func (it testIterator) Cursor() search.Cursor { return nil }
 
 func TestExecutor(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
@@ -55,14 +62,19 @@ func TestExecutor(t *testing.T) {
 		r.EXPECT().Close().Return(nil),
 	)
 
-	e := NewExecutor(rs).(*executor)
+	e := NewExecutor(rs, NewOptions()).(*executor)
 
 	// Override newIteratorFn to return test iterator.
-	e.newIteratorFn = func(_ search.Searcher, _ index.Readers) (doc.Iterator, error) {
+	e.newIteratorFn = func(
+		_ search.Searcher,
+		_ index.Readers,
+		_ []byte,
+		_ search.ExecuteOptions,
+	) (search.ResultIterator, error) {
 		return newTestIterator(), nil
 	}
 
-	it, err := e.Execute(q)
+	it, err := e.Execute(q, search.ExecuteOptions{})
 	require.NoError(t, err)
 
 	err = it.Close()
@@ -71,3 +83,197 @@ func TestExecutor(t *testing.T) {
 	err = e.Close()
 	require.NoError(t, err)
 }
+
+func TestExecutorCount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	firstPL := roaring.NewPostingsList()
+	firstPL.Insert(42)
+	firstPL.Insert(47)
+	secondPL := roaring.NewPostingsList()
+	secondPL.Insert(67)
+
+	var (
+		q        = search.NewMockQuery(mockCtrl)
+		searcher = search.NewMockSearcher(mockCtrl)
+		r1       = index.NewMockReader(mockCtrl)
+		r2       = index.NewMockReader(mockCtrl)
+		rs       = index.Readers{r1, r2}
+	)
+	gomock.InOrder(
+		q.EXPECT().Searcher(rs).Return(searcher, nil),
+		searcher.EXPECT().Next().Return(true),
+		searcher.EXPECT().Current().Return(firstPL),
+		searcher.EXPECT().Next().Return(true),
+		searcher.EXPECT().Current().Return(secondPL),
+		searcher.EXPECT().Next().Return(false),
+		searcher.EXPECT().Err().Return(nil),
+
+		r1.EXPECT().Close().Return(nil),
+		r2.EXPECT().Close().Return(nil),
+	)
+
+	// The documents should never be retrieved from the readers.
+	e := NewExecutor(rs, NewOptions())
+	count, err := e.Count(q)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	require.NoError(t, e.Close())
+	_, err = e.Count(q)
+	require.Equal(t, errExecutorClosed, err)
+}
+
+func TestExecutorLimitWithWorkerPool(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	workerPool := xsync.NewWorkerPool(2)
+	workerPool.Init()
+
+	pl := roaring.NewPostingsList()
+	pl.Insert(0)
+	pl.Insert(1)
+
+	var (
+		q       = search.NewMockQuery(mockCtrl)
+		s       = search.NewMockSearcher(mockCtrl)
+		docs    = index.NewMockIDDocIterator(mockCtrl)
+		d       = doc.Document{ID: []byte("apple")}
+		readers index.Readers
+	)
+	for i := 0; i < 3; i++ {
+		r := index.NewMockReader(mockCtrl)
+		readers = append(readers, r)
+		r.EXPECT().Close().Return(nil)
+
+		// The limit is reached within the first reader so the query should not be
+		// evaluated against the remaining readers.
+		if i == 0 {
+			q.EXPECT().Searcher(index.Readers{r}).Return(s, nil)
+			r.EXPECT().Docs(pl).Return(docs, nil)
+			continue
+		}
+		q.EXPECT().Searcher(index.Readers{r}).Return(search.NewMockSearcher(mockCtrl), nil)
+	}
+	gomock.InOrder(
+		s.EXPECT().Next().Return(true),
+		s.EXPECT().Current().Return(pl),
+		s.EXPECT().Next().Return(false),
+		s.EXPECT().Err().Return(nil),
+	)
+	gomock.InOrder(
+		docs.EXPECT().Next().Return(true),
+		docs.EXPECT().Current().Return(d),
+		docs.EXPECT().PostingsID().Return(postings.ID(0)),
+		docs.EXPECT().Next().Return(true),
+		docs.EXPECT().Current().Return(d),
+		docs.EXPECT().Close().Return(nil),
+	)
+
+	e := NewExecutor(readers, NewOptions().SetWorkerPool(workerPool).SetReadAhead(0))
+	it, err := e.Execute(q, search.ExecuteOptions{Limit: 1})
+	require.NoError(t, err)
+	require.True(t, it.Next())
+	require.Equal(t, d, it.Current())
+	require.False(t, it.Next())
+	require.True(t, it.LimitExceeded())
+	require.NoError(t, it.Close())
+
+	require.NoError(t, e.Close())
+}
+
+func TestExecutorCountSimplifiesQuery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pl := roaring.NewPostingsList()
+	pl.Insert(42)
+
+	var (
+		field = []byte("fruit")
+		r     = index.NewMockReader(mockCtrl)
+	)
+	gomock.InOrder(
+		// The literal regexp should be matched as a term.
+		r.EXPECT().MatchTerm(field, []byte("apple")).Return(pl, nil),
+		r.EXPECT().Close().Return(nil),
+	)
+
+	e := NewExecutor(index.Readers{r}, NewOptions())
+	count, err := e.Count(query.MustCreateRegexpQuery(field, []byte("apple")))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// The contradiction should not be evaluated against the reader.
+	term := query.NewTermQuery(field, []byte("apple"))
+	count, err = e.Count(query.NewConjunctionQuery([]search.Query{
+		term, query.NewNegationQuery(term),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	require.NoError(t, e.Close())
+}
+
+func TestExecutorPagination(t *testing.T) {
+	var (
+		ids     = []string{"apple", "banana", "grape", "lemon", "orange"}
+		readers index.Readers
+	)
+	for _, batch := range [][]string{ids[:3], ids[3:]} {
+		seg, err := mem.NewSegment(0, mem.NewOptions())
+		require.NoError(t, err)
+		for _, id := range batch {
+			_, err := seg.Insert(doc.Document{
+				ID: []byte(id),
+				Fields: []doc.Field{
+					doc.Field{
+						Name:  []byte("fruit"),
+						Value: []byte(id),
+					},
+				},
+			})
+			require.NoError(t, err)
+		}
+		r, err := seg.Reader()
+		require.NoError(t, err)
+		readers = append(readers, r)
+	}
+
+	e := NewExecutor(readers, NewOptions())
+	q := query.NewFieldQuery([]byte("fruit"))
+
+	var (
+		actual []string
+		opts   = search.ExecuteOptions{Limit: 2}
+	)
+	for {
+		it, err := e.Execute(q, opts)
+		require.NoError(t, err)
+
+		var page []string
+		for it.Next() {
+			page = append(page, string(it.Current().ID))
+		}
+		require.NoError(t, it.Err())
+		require.True(t, len(page) <= opts.Limit)
+		actual = append(actual, page...)
+
+		opts.Cursor = it.Cursor()
+		exceeded := it.LimitExceeded()
+		require.NoError(t, it.Close())
+		if !exceeded {
+			break
+		}
+	}
+	require.Equal(t, ids, actual)
+
+	// A cursor from a different snapshot should be rejected.
+	other := NewExecutor(readers, NewOptions())
+	_, err := other.Execute(q, opts)
+	require.Equal(t, ErrCursorSnapshotChanged, err)
+
+	require.NoError(t, e.Close())
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search"
+)
+
+func (e *executor) Facet(q search.Query, req search.FacetRequest) (search.FacetResults, error) {
+	e.RLock()
+	defer e.RUnlock()
+	if e.closed {
+		return search.FacetResults{}, errExecutorClosed
+	}
+
+	// The query is evaluated once per reader and its postings list is shared by each of
+	// the requested fields.
+	counts := make([]map[string]int, len(req.Fields))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	err := e.forEachPostingsListWithRLock(q, func(r index.Reader, pl postings.List) error {
+		if pl.IsEmpty() {
+			return nil
+		}
+
+		for i, field := range req.Fields {
+			iter, err := r.FieldTerms(field)
+			if err != nil {
+				return err
+			}
+
+			// Every matching term is required to compute the top terms by count.
+			if _, err := aggregateTerms(iter, pl, 0, counts[i]); err != nil {
+				iter.Close()
+				return err
+			}
+			if err := iter.Close(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return search.FacetResults{}, err
+	}
+
+	results := search.FacetResults{
+		Facets: make([]search.Facet, 0, len(req.Fields)),
+	}
+	for i, field := range req.Fields {
+		results.Facets = append(results.Facets, newFacet(field, counts[i], req.Size))
+	}
+	return results, nil
+}
+
+// newFacet returns the facet for a field with the given term counts, keeping only the
+// size terms with the highest counts.
+func newFacet(field []byte, counts map[string]int, size int) search.Facet {
+	terms := make([]search.AggregateTerm, 0, len(counts))
+	for term, count := range counts {
+		terms = append(terms, search.AggregateTerm{
+			Term:  []byte(term),
+			Count: count,
+		})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return bytes.Compare(terms[i].Term, terms[j].Term) < 0
+	})
+
+	facet := search.Facet{
+		Field: field,
+		Terms: terms,
+	}
+	if size > 0 && len(terms) > size {
+		facet.Terms = terms[:size]
+		facet.LimitExceeded = true
+	}
+	return facet
+}
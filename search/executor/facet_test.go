@@ -0,0 +1,115 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/query"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorFacet(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    search.Query
+		req      search.FacetRequest
+		expected search.FacetResults
+	}{
+		{
+			name:  "all documents",
+			query: query.NewFieldQuery([]byte("fruit")),
+			req: search.FacetRequest{
+				Fields: [][]byte{[]byte("region"), []byte("color")},
+			},
+			expected: search.FacetResults{
+				Facets: []search.Facet{
+					search.Facet{
+						Field: []byte("region"),
+						Terms: newAggregateTerms("us", 3, "eu", 2, "ap", 1),
+					},
+					search.Facet{
+						Field: []byte("color"),
+						Terms: newAggregateTerms("red", 3, "yellow", 2, "green", 1),
+					},
+				},
+			},
+		},
+		{
+			name:  "filtered documents with size",
+			query: query.NewTermQuery([]byte("color"), []byte("red")),
+			req: search.FacetRequest{
+				Fields: [][]byte{[]byte("region"), []byte("fruit"), []byte("unknown")},
+				Size:   1,
+			},
+			expected: search.FacetResults{
+				Facets: []search.Facet{
+					search.Facet{
+						Field: []byte("region"),
+						Terms: newAggregateTerms("us", 2),
+						// The "eu" region also matches.
+						LimitExceeded: true,
+					},
+					search.Facet{
+						Field: []byte("fruit"),
+						// Ties are broken by term order.
+						Terms:         newAggregateTerms("apple", 1),
+						LimitExceeded: true,
+					},
+					search.Facet{
+						Field: []byte("unknown"),
+						Terms: []search.AggregateTerm{},
+					},
+				},
+			},
+		},
+		{
+			name:  "no matching documents",
+			query: query.NewTermQuery([]byte("fruit"), []byte("kiwi")),
+			req: search.FacetRequest{
+				Fields: [][]byte{[]byte("region")},
+			},
+			expected: search.FacetResults{
+				Facets: []search.Facet{
+					search.Facet{
+						Field: []byte("region"),
+						Terms: []search.AggregateTerm{},
+					},
+				},
+			},
+		},
+	}
+
+	e := NewExecutor(newAggregateTestReaders(t), NewOptions())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := e.Facet(test.query, test.req)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	require.NoError(t, e.Close())
+	_, err := e.Facet(query.NewFieldQuery([]byte("fruit")), search.FacetRequest{})
+	require.Equal(t, errExecutorClosed, err)
+}
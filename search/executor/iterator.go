@@ -17,15 +17,18 @@
 // LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
-
 package executor
 
 import (
 	"errors"
+	"io"
 
 	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/search"
+
+	xerrors "github.com/m3db/m3x/errors"
 )
 
 var (
@@ -36,19 +39,49 @@ var (
 type iterator struct {
 	searcher search.Searcher
 	readers  index.Readers
+	limit    int
+
+	// start is the position to resume iteration after, if resuming from a cursor.
+	start    *cursor
+	snapshot []byte
 
 	idx      int
+	count    int
 	currDoc  doc.Document
-	currIter doc.Iterator
-
-	err    error
-	closed bool
+	currIter index.IDDocIterator
+	currIdx  int
+	currID   postings.ID
+	cursor   search.Cursor
+
+	done          bool
+	limitExceeded bool
+	err           error
+	closed        bool
 }
 
-func newIterator(s search.Searcher, rs index.Readers) (doc.Iterator, error) {
+func newIterator(
+	s search.Searcher,
+	rs index.Readers,
+	snapshot []byte,
+	opts search.ExecuteOptions,
+) (search.ResultIterator, error) {
 	it := &iterator{
 		searcher: s,
 		readers:  rs,
+		limit:    opts.Limit,
+		snapshot: snapshot,
+		cursor:   opts.Cursor,
+	}
+
+	if opts.Cursor != nil {
+		start, err := decodeCursor(opts.Cursor, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if start.readerIdx >= len(rs) {
+			return nil, errInvalidCursor
+		}
+		it.start = &start
 	}
 
 	currIter, err := it.nextIter()
@@ -60,15 +93,39 @@ func newIterator(s search.Searcher, rs index.Readers) (doc.Iterator, error) {
 }
 
 func (it *iterator) Next() bool {
-	if it.closed || it.err != nil || it.idx == len(it.readers) {
+	if it.closed || it.done || it.err != nil || it.idx == len(it.readers) {
 		return false
 	}
 
+	if it.limit > 0 && it.count == it.limit {
+		// Check whether there are any documents remaining so we can report whether the
+		// limit was exceeded, and stop pulling from the searcher and readers after that.
+		_, ok := it.nextDoc()
+		it.limitExceeded = ok
+		it.done = true
+		return false
+	}
+
+	d, ok := it.nextDoc()
+	if !ok {
+		return false
+	}
+	it.currDoc = d
+	it.currIdx = it.idx
+	it.currID = it.currIter.PostingsID()
+	it.cursor = nil
+	it.count++
+	return true
+}
+
+// nextDoc returns the next document matched by the searcher, advancing through the
+// readers as each of their document iterators is exhausted.
+func (it *iterator) nextDoc() (doc.Document, bool) {
 	for !it.currIter.Next() {
 		// Check if the current iterator encountered an error.
 		if err := it.currIter.Err(); err != nil {
 			it.err = err
-			return false
+			return doc.Document{}, false
 		}
 
 		// Close current iterator now that we are finished with it.
@@ -76,24 +133,23 @@ func (it *iterator) Next() bool {
 		it.currIter = nil
 		if err != nil {
 			it.err = err
-			return false
+			return doc.Document{}, false
 		}
 
 		it.idx++
 		iter, err := it.nextIter()
 		if err != nil {
 			it.err = err
-			return false
+			return doc.Document{}, false
 		}
 
 		if iter == nil {
-			return false
+			return doc.Document{}, false
 		}
 		it.currIter = iter
 	}
 
-	it.currDoc = it.currIter.Current()
-	return true
+	return it.currIter.Current(), true
 }
 
 func (it *iterator) Current() doc.Document {
@@ -104,42 +160,83 @@ func (it *iterator) Err() error {
 	return it.err
 }
 
+func (it *iterator) LimitExceeded() bool {
+	return it.limitExceeded
+}
+
+func (it *iterator) Cursor() search.Cursor {
+	if it.count == 0 {
+		return it.cursor
+	}
+	if it.cursor == nil {
+		// The cursor is encoded lazily since most callers only need it for the last
+		// document they consume.
+		it.cursor = cursor{
+			snapshot:  it.snapshot,
+			readerIdx: it.currIdx,
+			id:        it.currID,
+		}.encode()
+	}
+	return it.cursor
+}
+
 func (it *iterator) Close() error {
-	var err error
+	var multiErr xerrors.MultiError
 	if it.currIter != nil {
-		err = it.currIter.Close()
+		multiErr = multiErr.Add(it.currIter.Close())
 	}
-	return err
+	// Searchers which evaluate readers ahead of time must be told to stop doing so.
+	if closer, ok := it.searcher.(io.Closer); ok {
+		multiErr = multiErr.Add(closer.Close())
+	}
+	return multiErr.FinalError()
 }
 
 // nextIter gets the next document iterator by getting the next postings list from
 // the it's searcher and then getting the documents for that postings list from the
 // corresponding reader associated with that postings list. It also validates that
 // the number of postings lists returned by the searcher is equal to the number of
-// readers that the iterator is searching over.
-func (it *iterator) nextIter() (doc.Iterator, error) {
-	if !it.searcher.Next() {
-		if err := it.searcher.Err(); err != nil {
-			return nil, err
+// readers that the iterator is searching over. When resuming from a cursor, readers
+// before the cursor's reader are skipped along with any documents up to and including
+// the cursor's postings ID.
+func (it *iterator) nextIter() (index.IDDocIterator, error) {
+	for {
+		if !it.searcher.Next() {
+			if err := it.searcher.Err(); err != nil {
+				return nil, err
+			}
+
+			// Check that the Searcher hasn't returned too few postings lists.
+			if it.idx != len(it.readers) {
+				return nil, errTooManyReaders
+			}
+
+			return nil, nil
 		}
 
-		// Check that the Searcher hasn't returned too few postings lists.
-		if it.idx != len(it.readers) {
-			return nil, errTooManyReaders
+		// Check that the Searcher hasn't returned too many postings lists.
+		if it.idx == len(it.readers) {
+			return nil, errNotEnoughReaders
 		}
 
-		return nil, nil
-	}
-
-	// Check that the Searcher hasn't returned too many postings lists.
-	if it.idx == len(it.readers) {
-		return nil, errNotEnoughReaders
-	}
+		pl := it.searcher.Current()
+		if it.start != nil {
+			if it.idx < it.start.readerIdx {
+				it.idx++
+				continue
+			}
+
+			if it.idx == it.start.readerIdx {
+				remaining := pl.Clone()
+				remaining.RemoveRange(0, it.start.id+1)
+				pl = remaining
+			}
+		}
 
-	pl := it.searcher.Current()
-	iter, err := it.readers[it.idx].Docs(pl)
-	if err != nil {
-		return nil, err
+		iter, err := it.readers[it.idx].Docs(pl)
+		if err != nil {
+			return nil, err
+		}
+		return iter, nil
 	}
-	return iter, nil
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/m3db/m3ninx/doc"
 	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/postings/roaring"
 	"github.com/m3db/m3ninx/search"
 
@@ -32,6 +33,8 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var testSnapshot = []byte("snapshot")
+
 func TestIterator(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -81,19 +84,22 @@ func TestIterator(t *testing.T) {
 		},
 	}
 
-	firstDocIter := doc.NewMockIterator(mockCtrl)
-	secondDocIter := doc.NewMockIterator(mockCtrl)
+	firstDocIter := index.NewMockIDDocIterator(mockCtrl)
+	secondDocIter := index.NewMockIDDocIterator(mockCtrl)
 	gomock.InOrder(
 		firstDocIter.EXPECT().Next().Return(true),
 		firstDocIter.EXPECT().Current().Return(docs[0]),
+		firstDocIter.EXPECT().PostingsID().Return(postings.ID(0)),
 		firstDocIter.EXPECT().Next().Return(true),
 		firstDocIter.EXPECT().Current().Return(docs[1]),
+		firstDocIter.EXPECT().PostingsID().Return(postings.ID(1)),
 		firstDocIter.EXPECT().Next().Return(false),
 		firstDocIter.EXPECT().Err().Return(nil),
 		firstDocIter.EXPECT().Close().Return(nil),
 
 		secondDocIter.EXPECT().Next().Return(true),
 		secondDocIter.EXPECT().Current().Return(docs[2]),
+		secondDocIter.EXPECT().PostingsID().Return(postings.ID(2)),
 		secondDocIter.EXPECT().Next().Return(false),
 		secondDocIter.EXPECT().Err().Return(nil),
 		secondDocIter.EXPECT().Close().Return(nil),
@@ -109,7 +115,7 @@ func TestIterator(t *testing.T) {
 	readers := index.Readers{firstReader, secondReader}
 
 	// Construct iterator and run tests.
-	iter, err := newIterator(searcher, readers)
+	iter, err := newIterator(searcher, readers, testSnapshot, search.ExecuteOptions{})
 	require.NoError(t, err)
 
 	require.True(t, iter.Next())
@@ -120,6 +126,256 @@ func TestIterator(t *testing.T) {
 	require.Equal(t, docs[2], iter.Current())
 
 	require.False(t, iter.Next())
+	require.False(t, iter.LimitExceeded())
 	require.NoError(t, iter.Err())
 	require.NoError(t, iter.Close())
 }
+
+func TestIteratorLimit(t *testing.T) {
+	docs := []doc.Document{
+		doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("apple"),
+					Value: []byte("red"),
+				},
+			},
+		},
+		doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("banana"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+		doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("carrot"),
+					Value: []byte("orange"),
+				},
+			},
+		},
+	}
+
+	t.Run("limit exceeded within a reader", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		firstPL := roaring.NewPostingsList()
+		searcher := search.NewMockSearcher(mockCtrl)
+		gomock.InOrder(
+			searcher.EXPECT().Next().Return(true),
+			searcher.EXPECT().Current().Return(firstPL),
+		)
+
+		firstDocIter := index.NewMockIDDocIterator(mockCtrl)
+		gomock.InOrder(
+			firstDocIter.EXPECT().Next().Return(true),
+			firstDocIter.EXPECT().Current().Return(docs[0]),
+			firstDocIter.EXPECT().PostingsID().Return(postings.ID(0)),
+			firstDocIter.EXPECT().Next().Return(true),
+			firstDocIter.EXPECT().Current().Return(docs[1]),
+			firstDocIter.EXPECT().Close().Return(nil),
+		)
+
+		// The second reader should never be queried since the limit is reached in the first.
+		firstReader := index.NewMockReader(mockCtrl)
+		firstReader.EXPECT().Docs(firstPL).Return(firstDocIter, nil)
+		secondReader := index.NewMockReader(mockCtrl)
+		readers := index.Readers{firstReader, secondReader}
+
+		iter, err := newIterator(searcher, readers, testSnapshot, search.ExecuteOptions{Limit: 1})
+		require.NoError(t, err)
+
+		require.True(t, iter.Next())
+		require.Equal(t, docs[0], iter.Current())
+
+		require.False(t, iter.Next())
+		require.True(t, iter.LimitExceeded())
+		require.False(t, iter.Next())
+		require.NoError(t, iter.Err())
+		require.NoError(t, iter.Close())
+	})
+
+	t.Run("limit equal to number of documents", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		firstPL := roaring.NewPostingsList()
+		secondPL := roaring.NewPostingsList()
+		searcher := search.NewMockSearcher(mockCtrl)
+		gomock.InOrder(
+			searcher.EXPECT().Next().Return(true),
+			searcher.EXPECT().Current().Return(firstPL),
+			searcher.EXPECT().Next().Return(true),
+			searcher.EXPECT().Current().Return(secondPL),
+			searcher.EXPECT().Next().Return(false),
+			searcher.EXPECT().Err().Return(nil),
+		)
+
+		firstDocIter := index.NewMockIDDocIterator(mockCtrl)
+		secondDocIter := index.NewMockIDDocIterator(mockCtrl)
+		gomock.InOrder(
+			firstDocIter.EXPECT().Next().Return(true),
+			firstDocIter.EXPECT().Current().Return(docs[0]),
+			firstDocIter.EXPECT().PostingsID().Return(postings.ID(0)),
+			firstDocIter.EXPECT().Next().Return(true),
+			firstDocIter.EXPECT().Current().Return(docs[1]),
+			firstDocIter.EXPECT().PostingsID().Return(postings.ID(1)),
+			firstDocIter.EXPECT().Next().Return(false),
+			firstDocIter.EXPECT().Err().Return(nil),
+			firstDocIter.EXPECT().Close().Return(nil),
+
+			secondDocIter.EXPECT().Next().Return(true),
+			secondDocIter.EXPECT().Current().Return(docs[2]),
+			secondDocIter.EXPECT().PostingsID().Return(postings.ID(2)),
+			secondDocIter.EXPECT().Next().Return(false),
+			secondDocIter.EXPECT().Err().Return(nil),
+			secondDocIter.EXPECT().Close().Return(nil),
+		)
+
+		firstReader := index.NewMockReader(mockCtrl)
+		secondReader := index.NewMockReader(mockCtrl)
+		gomock.InOrder(
+			firstReader.EXPECT().Docs(firstPL).Return(firstDocIter, nil),
+
+			secondReader.EXPECT().Docs(secondPL).Return(secondDocIter, nil),
+		)
+		readers := index.Readers{firstReader, secondReader}
+
+		iter, err := newIterator(searcher, readers, testSnapshot, search.ExecuteOptions{Limit: 3})
+		require.NoError(t, err)
+
+		require.True(t, iter.Next())
+		require.Equal(t, docs[0], iter.Current())
+		require.True(t, iter.Next())
+		require.Equal(t, docs[1], iter.Current())
+		require.True(t, iter.Next())
+		require.Equal(t, docs[2], iter.Current())
+
+		require.False(t, iter.Next())
+		require.False(t, iter.LimitExceeded())
+		require.NoError(t, iter.Err())
+		require.NoError(t, iter.Close())
+	})
+}
+
+func TestIteratorCursor(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	docs := []doc.Document{
+		doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("apple"),
+					Value: []byte("red"),
+				},
+			},
+		},
+		doc.Document{
+			Fields: []doc.Field{
+				doc.Field{
+					Name:  []byte("banana"),
+					Value: []byte("yellow"),
+				},
+			},
+		},
+	}
+
+	// Resume after the document with postings ID 5 in the second reader.
+	start := cursor{snapshot: testSnapshot, readerIdx: 1, id: 5}.encode()
+
+	firstPL := roaring.NewPostingsList()
+	firstPL.Insert(1)
+	secondPL := roaring.NewPostingsList()
+	secondPL.Insert(4)
+	secondPL.Insert(5)
+	secondPL.Insert(6)
+	thirdPL := roaring.NewPostingsList()
+	thirdPL.Insert(2)
+
+	searcher := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		searcher.EXPECT().Next().Return(true),
+		searcher.EXPECT().Current().Return(firstPL),
+		searcher.EXPECT().Next().Return(true),
+		searcher.EXPECT().Current().Return(secondPL),
+		searcher.EXPECT().Next().Return(true),
+		searcher.EXPECT().Current().Return(thirdPL),
+		searcher.EXPECT().Next().Return(false),
+		searcher.EXPECT().Err().Return(nil),
+	)
+
+	secondDocIter := index.NewMockIDDocIterator(mockCtrl)
+	thirdDocIter := index.NewMockIDDocIterator(mockCtrl)
+	gomock.InOrder(
+		secondDocIter.EXPECT().Next().Return(true),
+		secondDocIter.EXPECT().Current().Return(docs[0]),
+		secondDocIter.EXPECT().PostingsID().Return(postings.ID(6)),
+		secondDocIter.EXPECT().Next().Return(false),
+		secondDocIter.EXPECT().Err().Return(nil),
+		secondDocIter.EXPECT().Close().Return(nil),
+
+		thirdDocIter.EXPECT().Next().Return(true),
+		thirdDocIter.EXPECT().Current().Return(docs[1]),
+		thirdDocIter.EXPECT().PostingsID().Return(postings.ID(2)),
+		thirdDocIter.EXPECT().Next().Return(false),
+		thirdDocIter.EXPECT().Err().Return(nil),
+		thirdDocIter.EXPECT().Close().Return(nil),
+	)
+
+	// The first reader is skipped entirely and the second reader is only queried for the
+	// documents after the cursor.
+	firstReader := index.NewMockReader(mockCtrl)
+	secondReader := index.NewMockReader(mockCtrl)
+	thirdReader := index.NewMockReader(mockCtrl)
+	gomock.InOrder(
+		secondReader.EXPECT().Docs(gomock.Any()).Do(func(pl postings.List) {
+			expected := roaring.NewPostingsList()
+			expected.Insert(6)
+			require.True(t, expected.Equal(pl))
+		}).Return(secondDocIter, nil),
+
+		thirdReader.EXPECT().Docs(thirdPL).Return(thirdDocIter, nil),
+	)
+	readers := index.Readers{firstReader, secondReader, thirdReader}
+
+	iter, err := newIterator(searcher, readers, testSnapshot, search.ExecuteOptions{Cursor: start})
+	require.NoError(t, err)
+
+	// The cursor is unchanged until a document is returned.
+	require.Equal(t, start, iter.Cursor())
+
+	require.True(t, iter.Next())
+	require.Equal(t, docs[0], iter.Current())
+	c, err := decodeCursor(iter.Cursor(), testSnapshot)
+	require.NoError(t, err)
+	require.Equal(t, cursor{snapshot: testSnapshot, readerIdx: 1, id: 6}, c)
+
+	require.True(t, iter.Next())
+	require.Equal(t, docs[1], iter.Current())
+	c, err = decodeCursor(iter.Cursor(), testSnapshot)
+	require.NoError(t, err)
+	require.Equal(t, cursor{snapshot: testSnapshot, readerIdx: 2, id: 2}, c)
+
+	require.False(t, iter.Next())
+	require.NoError(t, iter.Err())
+	require.NoError(t, iter.Close())
+}
+
+func TestIteratorCursorSnapshotChanged(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		searcher = search.NewMockSearcher(mockCtrl)
+		readers  = index.Readers{index.NewMockReader(mockCtrl)}
+		start    = cursor{snapshot: []byte("other"), readerIdx: 0, id: 5}.encode()
+	)
+
+	_, err := newIterator(searcher, readers, testSnapshot, search.ExecuteOptions{Cursor: start})
+	require.Equal(t, ErrCursorSnapshotChanged, err)
+}
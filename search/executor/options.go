@@ -0,0 +1,81 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	xsync "github.com/m3db/m3x/sync"
+)
+
+const (
+	defaultReadAhead = 4
+)
+
+// Options is a collection of knobs for an Executor.
+type Options interface {
+	// SetWorkerPool sets the worker pool used to evaluate a query over each of the
+	// Executor's readers concurrently. If the worker pool is nil, the default, the
+	// readers are evaluated sequentially.
+	SetWorkerPool(value xsync.WorkerPool) Options
+
+	// WorkerPool returns the worker pool used to evaluate a query over each of the
+	// Executor's readers concurrently.
+	WorkerPool() xsync.WorkerPool
+
+	// SetReadAhead sets the maximum number of readers past the one being consumed which
+	// are evaluated on the worker pool ahead of time.
+	SetReadAhead(value int) Options
+
+	// ReadAhead returns the maximum number of readers past the one being consumed which
+	// are evaluated on the worker pool ahead of time.
+	ReadAhead() int
+}
+
+type opts struct {
+	workerPool xsync.WorkerPool
+	readAhead  int
+}
+
+// NewOptions returns new options.
+func NewOptions() Options {
+	return &opts{
+		readAhead: defaultReadAhead,
+	}
+}
+
+func (o *opts) SetWorkerPool(v xsync.WorkerPool) Options {
+	opts := *o
+	opts.workerPool = v
+	return &opts
+}
+
+func (o *opts) WorkerPool() xsync.WorkerPool {
+	return o.workerPool
+}
+
+func (o *opts) SetReadAhead(v int) Options {
+	opts := *o
+	opts.readAhead = v
+	return &opts
+}
+
+func (o *opts) ReadAhead() int {
+	return o.readAhead
+}
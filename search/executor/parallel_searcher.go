@@ -0,0 +1,214 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"sync"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/search"
+
+	xsync "github.com/m3db/m3x/sync"
+)
+
+// evaluations tracks the readers being evaluated on a worker pool so their readers are
+// not closed while they are still in use.
+type evaluations struct {
+	sync.Mutex
+
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// start registers a new evaluation, returning false if evaluations have been closed.
+func (e *evaluations) start() bool {
+	e.Lock()
+	defer e.Unlock()
+	if e.closed {
+		return false
+	}
+	e.wg.Add(1)
+	return true
+}
+
+// done marks an evaluation registered with start as complete.
+func (e *evaluations) done() {
+	e.wg.Done()
+}
+
+// closeAndWait prevents any new evaluations from starting and waits for the evaluations
+// in progress to complete.
+func (e *evaluations) closeAndWait() {
+	e.Lock()
+	e.closed = true
+	e.Unlock()
+	e.wg.Wait()
+}
+
+// parallelSearcherResult is the result of evaluating a query over a single reader.
+type parallelSearcherResult struct {
+	pl   postings.List
+	err  error
+	done chan struct{}
+}
+
+// parallelSearcher is a Searcher which evaluates a query over each of its readers
+// concurrently on a worker pool while still returning the postings lists in the order
+// of the readers. Readers are only scheduled once the consumer reaches them or is at
+// most readAhead readers behind them, so a consumer which stops early, e.g. because it
+// reached a limit, does not pay for evaluating the remaining readers. It is not safe
+// for concurrent access.
+type parallelSearcher struct {
+	searchers   search.Searchers
+	results     []parallelSearcherResult
+	workerPool  xsync.WorkerPool
+	evaluations *evaluations
+	readAhead   int
+	scheduled   int
+
+	idx    int
+	curr   postings.List
+	err    error
+	closed bool
+}
+
+// newParallelSearcher returns a new Searcher which evaluates the query over each of
+// the readers concurrently using the provided worker pool, scheduling at most readAhead
+// readers ahead of the reader being consumed.
+func newParallelSearcher(
+	q search.Query,
+	rs index.Readers,
+	workerPool xsync.WorkerPool,
+	evaluations *evaluations,
+	readAhead int,
+) (search.Searcher, error) {
+	searchers := make(search.Searchers, 0, len(rs))
+	for _, r := range rs {
+		s, err := q.Searcher(index.Readers{r})
+		if err != nil {
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+
+	results := make([]parallelSearcherResult, len(rs))
+	for i := range results {
+		results[i].done = make(chan struct{})
+	}
+
+	return &parallelSearcher{
+		searchers:   searchers,
+		results:     results,
+		workerPool:  workerPool,
+		evaluations: evaluations,
+		readAhead:   readAhead,
+		idx:         -1,
+	}, nil
+}
+
+// evaluateSingleReader returns the only postings list of a Searcher over a single reader.
+func evaluateSingleReader(s search.Searcher) (postings.List, error) {
+	if !s.Next() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errTooManyReaders
+	}
+	pl := s.Current()
+
+	if s.Next() {
+		return nil, errNotEnoughReaders
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+func (s *parallelSearcher) Next() bool {
+	if s.closed || s.err != nil || s.idx == len(s.searchers)-1 {
+		return false
+	}
+
+	s.idx++
+	s.schedule(s.idx + s.readAhead)
+	if s.idx >= s.scheduled {
+		// The reader could not be scheduled because the Executor has been closed.
+		s.err = errExecutorClosed
+		return false
+	}
+
+	result := &s.results[s.idx]
+	<-result.done
+	if result.err != nil {
+		s.err = result.err
+		return false
+	}
+	s.curr = result.pl
+
+	// Release the postings list now that it's been handed over.
+	result.pl = nil
+	return true
+}
+
+// schedule schedules the readers in order up to and including the reader at index last
+// on the worker pool.
+func (s *parallelSearcher) schedule(last int) {
+	if last >= len(s.searchers) {
+		last = len(s.searchers) - 1
+	}
+
+	for ; s.scheduled <= last; s.scheduled++ {
+		if !s.evaluations.start() {
+			return
+		}
+
+		var (
+			searcher = s.searchers[s.scheduled]
+			result   = &s.results[s.scheduled]
+		)
+		s.workerPool.Go(func() {
+			defer s.evaluations.done()
+			result.pl, result.err = evaluateSingleReader(searcher)
+			close(result.done)
+		})
+	}
+}
+
+func (s *parallelSearcher) Current() postings.List {
+	return s.curr
+}
+
+func (s *parallelSearcher) Err() error {
+	return s.err
+}
+
+func (s *parallelSearcher) NumReaders() int {
+	return len(s.searchers)
+}
+
+// Close stops the searcher from scheduling any more readers. Readers which have already
+// been scheduled are evaluated to completion.
+func (s *parallelSearcher) Close() error {
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/postings"
+	"github.com/m3db/m3ninx/postings/roaring"
+	"github.com/m3db/m3ninx/search"
+
+	"github.com/golang/mock/gomock"
+	xsync "github.com/m3db/m3x/sync"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelSearcher(t *testing.T) {
+	for _, poolSize := range []int{1, 2, 8} {
+		t.Run(fmt.Sprintf("pool size %d", poolSize), func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			workerPool := xsync.NewWorkerPool(poolSize)
+			workerPool.Init()
+
+			var (
+				q       = search.NewMockQuery(mockCtrl)
+				readers index.Readers
+				pls     []postings.List
+			)
+			for i := 0; i < 5; i++ {
+				r := index.NewMockReader(mockCtrl)
+				readers = append(readers, r)
+
+				pl := roaring.NewPostingsList()
+				pl.Insert(postings.ID(i))
+				pls = append(pls, pl)
+
+				s := search.NewMockSearcher(mockCtrl)
+				gomock.InOrder(
+					s.EXPECT().Next().Return(true),
+					s.EXPECT().Current().Return(pl),
+					s.EXPECT().Next().Return(false),
+					s.EXPECT().Err().Return(nil),
+				)
+				q.EXPECT().Searcher(index.Readers{r}).Return(s, nil)
+			}
+
+			s, err := newParallelSearcher(q, readers, workerPool, &evaluations{}, defaultReadAhead)
+			require.NoError(t, err)
+			require.Equal(t, len(readers), s.NumReaders())
+
+			// The postings lists should be returned in the order of the readers.
+			for _, pl := range pls {
+				require.True(t, s.Next())
+				require.True(t, pl.Equal(s.Current()))
+			}
+			require.False(t, s.Next())
+			require.NoError(t, s.Err())
+		})
+	}
+}
+
+func TestParallelSearcherError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	workerPool := xsync.NewWorkerPool(2)
+	workerPool.Init()
+
+	var (
+		q        = search.NewMockQuery(mockCtrl)
+		first    = index.NewMockReader(mockCtrl)
+		second   = index.NewMockReader(mockCtrl)
+		firstPL  = roaring.NewPostingsList()
+		errQuery = errors.New("query error")
+	)
+
+	firstSearcher := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		firstSearcher.EXPECT().Next().Return(true),
+		firstSearcher.EXPECT().Current().Return(firstPL),
+		firstSearcher.EXPECT().Next().Return(false),
+		firstSearcher.EXPECT().Err().Return(nil),
+	)
+	secondSearcher := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		secondSearcher.EXPECT().Next().Return(false),
+		secondSearcher.EXPECT().Err().Return(errQuery),
+	)
+	q.EXPECT().Searcher(index.Readers{first}).Return(firstSearcher, nil)
+	q.EXPECT().Searcher(index.Readers{second}).Return(secondSearcher, nil)
+
+	s, err := newParallelSearcher(q, index.Readers{first, second}, workerPool, &evaluations{}, defaultReadAhead)
+	require.NoError(t, err)
+
+	require.True(t, s.Next())
+	require.True(t, firstPL.Equal(s.Current()))
+	require.False(t, s.Next())
+	require.Equal(t, errQuery, s.Err())
+}
+
+func TestParallelSearcherReadAhead(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	workerPool := xsync.NewWorkerPool(8)
+	workerPool.Init()
+
+	var (
+		q       = search.NewMockQuery(mockCtrl)
+		readers index.Readers
+		pls     []postings.List
+	)
+	for i := 0; i < 5; i++ {
+		r := index.NewMockReader(mockCtrl)
+		readers = append(readers, r)
+
+		pl := roaring.NewPostingsList()
+		pl.Insert(postings.ID(i))
+		pls = append(pls, pl)
+
+		// Only the first reader and the reader after it should be evaluated.
+		s := search.NewMockSearcher(mockCtrl)
+		if i < 2 {
+			gomock.InOrder(
+				s.EXPECT().Next().Return(true),
+				s.EXPECT().Current().Return(pl),
+				s.EXPECT().Next().Return(false),
+				s.EXPECT().Err().Return(nil),
+			)
+		}
+		q.EXPECT().Searcher(index.Readers{r}).Return(s, nil)
+	}
+
+	evals := &evaluations{}
+	s, err := newParallelSearcher(q, readers, workerPool, evals, 1)
+	require.NoError(t, err)
+
+	require.True(t, s.Next())
+	require.True(t, pls[0].Equal(s.Current()))
+
+	// The remaining readers should not be scheduled once the searcher is closed.
+	require.NoError(t, s.(io.Closer).Close())
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+
+	evals.closeAndWait()
+}
+
+func TestParallelSearcherEvaluationsClosed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	workerPool := xsync.NewWorkerPool(2)
+	workerPool.Init()
+
+	var (
+		q = search.NewMockQuery(mockCtrl)
+		r = index.NewMockReader(mockCtrl)
+	)
+	q.EXPECT().Searcher(index.Readers{r}).Return(search.NewMockSearcher(mockCtrl), nil).Times(2)
+
+	evals := &evaluations{}
+	s, err := newParallelSearcher(q, index.Readers{r, r}, workerPool, evals, defaultReadAhead)
+	require.NoError(t, err)
+
+	// No readers should be evaluated once the Executor has been closed.
+	evals.closeAndWait()
+	require.False(t, s.Next())
+	require.Equal(t, errExecutorClosed, s.Err())
+}
+
+func TestEvaluateSingleReader(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pl := roaring.NewPostingsList()
+
+	// A searcher which returns no postings lists.
+	s := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		s.EXPECT().Next().Return(false),
+		s.EXPECT().Err().Return(nil),
+	)
+	_, err := evaluateSingleReader(s)
+	require.Equal(t, errTooManyReaders, err)
+
+	// A searcher which returns more than one postings list.
+	s = search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		s.EXPECT().Next().Return(true),
+		s.EXPECT().Current().Return(pl),
+		s.EXPECT().Next().Return(true),
+	)
+	_, err = evaluateSingleReader(s)
+	require.Equal(t, errNotEnoughReaders, err)
+}
@@ -57,6 +57,24 @@ func unmarshal(q *querypb.Query) (search.Query, error) {
 	case *querypb.Query_Regexp:
 		return NewRegexpQuery(q.Regexp.Field, q.Regexp.Regexp)
 
+	case *querypb.Query_Prefix:
+		return NewPrefixQuery(q.Prefix.Field, q.Prefix.Prefix), nil
+
+	case *querypb.Query_TermRange:
+		return NewTermRangeQuery(
+			q.TermRange.Field,
+			q.TermRange.Min,
+			q.TermRange.Max,
+			q.TermRange.MinInclusive,
+			q.TermRange.MaxInclusive,
+		), nil
+
+	case *querypb.Query_Field:
+		return NewFieldQuery(q.Field.Field), nil
+
+	case *querypb.Query_Terms:
+		return NewTermsQuery(q.Terms.Field, q.Terms.Terms), nil
+
 	case *querypb.Query_Negation:
 		inner, err := unmarshal(q.Negation.Query)
 		if err != nil {
@@ -45,6 +45,7 @@ func NewConjunctionQuery(queries []search.Query) search.Query {
 		case *ConjuctionQuery:
 			// Merge conjunction queries into slice of top-level queries.
 			qs = append(qs, query.queries...)
+			ns = append(ns, query.negations...)
 			continue
 		case *NegationQuery:
 			ns = append(ns, query.query)
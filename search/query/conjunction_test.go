@@ -128,3 +128,27 @@ func TestConjunctionQueryEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestConjunctionQueryKeepsNegationsOfNestedConjunctions(t *testing.T) {
+	var (
+		apple = NewTermQuery([]byte("fruit"), []byte("apple"))
+		green = NewTermQuery([]byte("color"), []byte("green"))
+		red   = NewTermQuery([]byte("color"), []byte("red"))
+	)
+
+	// Flattening the nested conjunction must not drop its negation, otherwise the query
+	// would also match green apples.
+	q := NewConjunctionQuery([]search.Query{
+		NewConjunctionQuery([]search.Query{apple, NewNegationQuery(green)}),
+		red,
+	})
+
+	conj, ok := q.(*ConjuctionQuery)
+	require.True(t, ok)
+	require.Equal(t, []search.Query{apple, red}, conj.queries)
+	require.Equal(t, []search.Query{green}, conj.negations)
+
+	require.True(t, q.Equal(NewConjunctionQuery([]search.Query{
+		apple, red, NewNegationQuery(green),
+	})))
+}
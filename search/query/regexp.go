@@ -38,9 +38,11 @@ type RegexpQuery struct {
 	compiled *re.Regexp
 }
 
-// NewRegexpQuery constructs a new query for the given regular expression.
+// NewRegexpQuery constructs a new query for the given regular expression. The regular
+// expression must match a term in its entirety, e.g. "app" does not match "apple" but
+// "app.*" does, for every type of segment. See index.CompileRegexp.
 func NewRegexpQuery(field, regexp []byte) (search.Query, error) {
-	compiled, err := re.Compile(string(regexp))
+	compiled, err := index.CompileRegexp(regexp)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +56,7 @@ func NewRegexpQuery(field, regexp []byte) (search.Query, error) {
 
 // MustCreateRegexpQuery is like NewRegexpQuery but panics if the query cannot be created.
 func MustCreateRegexpQuery(field, regexp []byte) search.Query {
-	compiled, err := re.Compile(string(regexp))
+	compiled, err := index.CompileRegexp(regexp)
 	if err != nil {
 		panic(err)
 	}
@@ -66,6 +66,17 @@ func TestRegexpQuery(t *testing.T) {
 	}
 }
 
+func TestRegexpQueryMatchesWholeTerms(t *testing.T) {
+	q, err := NewRegexpQuery([]byte("fruit"), []byte("apple|pear"))
+	require.NoError(t, err)
+
+	compiled := q.(*RegexpQuery).compiled
+	require.True(t, compiled.MatchString("apple"))
+	require.True(t, compiled.MatchString("pear"))
+	require.False(t, compiled.MatchString("pineapple"))
+	require.False(t, compiled.MatchString("pears"))
+}
+
 func TestRegexpQueryEqual(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,224 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"regexp/syntax"
+
+	"github.com/m3db/m3ninx/search"
+)
+
+// Simplify returns a query which matches the same documents as the given query but is
+// cheaper to execute. It rewrites regexps which match a literal or a literal prefix as
+// term and prefix queries, removes double negations and single child conjunctions and
+// disjunctions, flattens nested conjunctions and disjunctions, removes duplicate clauses,
+// and replaces conjunctions which contain both a query and its negation, or a query which
+// cannot match any documents, with an empty disjunction.
+func Simplify(q search.Query) search.Query {
+	switch q := q.(type) {
+	case *RegexpQuery:
+		return simplifyRegexp(q)
+
+	case *TermsQuery:
+		switch len(q.terms) {
+		case 0:
+			return emptyQuery()
+		case 1:
+			return NewTermQuery(q.field, q.terms[0])
+		}
+		return q
+
+	case *NegationQuery:
+		inner := Simplify(q.query)
+		if n, ok := inner.(*NegationQuery); ok {
+			return n.query
+		}
+		return NewNegationQuery(inner)
+
+	case *ConjuctionQuery:
+		return simplifyConjunction(q)
+
+	case *DisjuctionQuery:
+		return simplifyDisjunction(q)
+	}
+
+	return q
+}
+
+// emptyQuery returns a query which matches no documents.
+func emptyQuery() search.Query {
+	return NewDisjunctionQuery(nil)
+}
+
+// isEmpty returns whether a simplified query matches no documents.
+func isEmpty(q search.Query) bool {
+	switch q := q.(type) {
+	case *ConjuctionQuery:
+		return len(q.queries) == 0
+	case *DisjuctionQuery:
+		return len(q.queries) == 0
+	}
+	return false
+}
+
+func simplifyConjunction(q *ConjuctionQuery) search.Query {
+	var qs, ns []search.Query
+
+	var add func(q search.Query)
+	add = func(q search.Query) {
+		switch q := q.(type) {
+		case *ConjuctionQuery:
+			for _, inner := range q.queries {
+				add(inner)
+			}
+			for _, inner := range q.negations {
+				ns = appendUnique(ns, inner)
+			}
+		case *NegationQuery:
+			ns = appendUnique(ns, q.query)
+		default:
+			qs = appendUnique(qs, q)
+		}
+	}
+
+	for _, inner := range q.queries {
+		add(Simplify(inner))
+	}
+	for _, inner := range q.negations {
+		add(Simplify(NewNegationQuery(inner)))
+	}
+
+	if len(qs) == 0 && len(ns) == 0 {
+		return emptyQuery()
+	}
+
+	for _, inner := range qs {
+		if isEmpty(inner) || contains(ns, inner) {
+			return emptyQuery()
+		}
+	}
+
+	switch {
+	case len(qs) == 1 && len(ns) == 0:
+		return qs[0]
+	case len(qs) == 0 && len(ns) == 1:
+		return NewNegationQuery(ns[0])
+	}
+
+	queries := make([]search.Query, 0, len(qs)+len(ns))
+	queries = append(queries, qs...)
+	for _, inner := range ns {
+		queries = append(queries, NewNegationQuery(inner))
+	}
+	return NewConjunctionQuery(queries)
+}
+
+func simplifyDisjunction(q *DisjuctionQuery) search.Query {
+	var qs []search.Query
+	for _, inner := range q.queries {
+		inner = Simplify(inner)
+		if d, ok := inner.(*DisjuctionQuery); ok {
+			for _, q := range d.queries {
+				qs = appendUnique(qs, q)
+			}
+			continue
+		}
+		if !isEmpty(inner) {
+			qs = appendUnique(qs, inner)
+		}
+	}
+
+	if len(qs) == 1 {
+		return qs[0]
+	}
+	return NewDisjunctionQuery(qs)
+}
+
+func contains(qs []search.Query, q search.Query) bool {
+	for _, existing := range qs {
+		if existing.Equal(q) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(qs []search.Query, q search.Query) []search.Query {
+	if contains(qs, q) {
+		return qs
+	}
+	return append(qs, q)
+}
+
+// simplifyRegexp rewrites a regexp query as a term query if the regexp only matches a
+// literal, or as a prefix query if it matches a literal followed by any string. Since
+// "." does not match a newline unless the s flag is set, the latter requires a regexp
+// such as "(?s)foo.*".
+func simplifyRegexp(q *RegexpQuery) search.Query {
+	parsed, err := syntax.Parse(string(q.regexp), syntax.Perl)
+	if err != nil {
+		return q
+	}
+	parsed = uncapture(parsed.Simplify())
+
+	subs := []*syntax.Regexp{parsed}
+	if parsed.Op == syntax.OpConcat {
+		subs = parsed.Sub
+	}
+
+	// Regexps must match whole terms so anchors at either end are redundant.
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	}
+	if len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+	}
+
+	switch {
+	case len(subs) == 1 && isLiteral(subs[0]):
+		return NewTermQuery(q.field, literal(subs[0]))
+	case len(subs) == 2 && isLiteral(subs[0]) && isAnyString(subs[1]):
+		return NewPrefixQuery(q.field, literal(subs[0]))
+	}
+
+	return q
+}
+
+func uncapture(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	return re
+}
+
+func isLiteral(re *syntax.Regexp) bool {
+	re = uncapture(re)
+	return re.Op == syntax.OpLiteral && re.Flags&syntax.FoldCase == 0
+}
+
+func literal(re *syntax.Regexp) []byte {
+	return []byte(string(uncapture(re).Rune))
+}
+
+func isAnyString(re *syntax.Regexp) bool {
+	re = uncapture(re)
+	return re.Op == syntax.OpStar && uncapture(re.Sub[0]).Op == syntax.OpAnyChar
+}
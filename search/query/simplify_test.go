@@ -0,0 +1,178 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplify(t *testing.T) {
+	var (
+		field  = []byte("fruit")
+		apple  = NewTermQuery(field, []byte("apple"))
+		banana = NewTermQuery(field, []byte("banana"))
+		cherry = NewTermQuery(field, []byte("cherry"))
+		empty  = NewDisjunctionQuery(nil)
+	)
+
+	tests := []struct {
+		name            string
+		input, expected search.Query
+	}{
+		{
+			name:     "literal regexp",
+			input:    MustCreateRegexpQuery(field, []byte("apple")),
+			expected: apple,
+		},
+		{
+			name:     "anchored literal regexp",
+			input:    MustCreateRegexpQuery(field, []byte(`^(app\.le)$`)),
+			expected: NewTermQuery(field, []byte("app.le")),
+		},
+		{
+			name:     "prefix regexp",
+			input:    MustCreateRegexpQuery(field, []byte("(?s)app.*")),
+			expected: NewPrefixQuery(field, []byte("app")),
+		},
+		{
+			name:     "prefix regexp excluding newlines",
+			input:    MustCreateRegexpQuery(field, []byte("app.*")),
+			expected: MustCreateRegexpQuery(field, []byte("app.*")),
+		},
+		{
+			name:     "case insensitive regexp",
+			input:    MustCreateRegexpQuery(field, []byte("(?i)apple")),
+			expected: MustCreateRegexpQuery(field, []byte("(?i)apple")),
+		},
+		{
+			name:     "single terms",
+			input:    NewTermsQuery(field, [][]byte{[]byte("apple")}),
+			expected: apple,
+		},
+		{
+			name:     "no terms",
+			input:    NewTermsQuery(field, nil),
+			expected: empty,
+		},
+		{
+			name:     "double negation",
+			input:    NewNegationQuery(NewNegationQuery(apple)),
+			expected: apple,
+		},
+		{
+			name: "single child wrappers",
+			input: NewDisjunctionQuery([]search.Query{
+				NewConjunctionQuery([]search.Query{
+					NewDisjunctionQuery([]search.Query{apple}),
+				}),
+			}),
+			expected: apple,
+		},
+		{
+			name: "nested disjunctions",
+			input: NewDisjunctionQuery([]search.Query{
+				apple,
+				NewConjunctionQuery([]search.Query{
+					NewDisjunctionQuery([]search.Query{banana, cherry}),
+				}),
+			}),
+			expected: NewDisjunctionQuery([]search.Query{apple, banana, cherry}),
+		},
+		{
+			name: "nested conjunctions",
+			input: NewConjunctionQuery([]search.Query{
+				apple,
+				NewDisjunctionQuery([]search.Query{
+					NewConjunctionQuery([]search.Query{banana, NewNegationQuery(cherry)}),
+				}),
+			}),
+			expected: NewConjunctionQuery([]search.Query{apple, banana, NewNegationQuery(cherry)}),
+		},
+		{
+			name: "duplicate clauses",
+			input: NewConjunctionQuery([]search.Query{
+				apple,
+				NewNegationQuery(banana),
+				MustCreateRegexpQuery(field, []byte("apple")),
+				NewNegationQuery(banana),
+				NewDisjunctionQuery([]search.Query{cherry, cherry}),
+			}),
+			expected: NewConjunctionQuery([]search.Query{apple, cherry, NewNegationQuery(banana)}),
+		},
+		{
+			name: "duplicate negations",
+			input: NewConjunctionQuery([]search.Query{
+				NewNegationQuery(apple),
+				NewNegationQuery(apple),
+			}),
+			expected: NewNegationQuery(apple),
+		},
+		{
+			name: "contradiction",
+			input: NewConjunctionQuery([]search.Query{
+				banana,
+				MustCreateRegexpQuery(field, []byte("apple")),
+				NewNegationQuery(apple),
+			}),
+			expected: empty,
+		},
+		{
+			name: "contradiction within disjunction",
+			input: NewDisjunctionQuery([]search.Query{
+				NewConjunctionQuery([]search.Query{apple, NewNegationQuery(apple)}),
+				banana,
+			}),
+			expected: banana,
+		},
+		{
+			name: "conjunction with empty query",
+			input: NewConjunctionQuery([]search.Query{
+				apple,
+				NewTermsQuery(field, nil),
+			}),
+			expected: empty,
+		},
+		{
+			name:     "empty conjunction",
+			input:    NewConjunctionQuery(nil),
+			expected: empty,
+		},
+		{
+			name:     "unchanged query",
+			input:    NewConjunctionQuery([]search.Query{apple, NewNegationQuery(banana)}),
+			expected: NewConjunctionQuery([]search.Query{apple, NewNegationQuery(banana)}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Simplify(test.input)
+			require.True(t, test.expected.Equal(actual), "expected %v, got %v", test.expected, actual)
+
+			// Equal ignores single child wrappers so compare the structure of the queries too.
+			require.Equal(t, test.expected.String(), actual.String())
+		})
+	}
+}
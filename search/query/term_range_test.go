@@ -0,0 +1,111 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/search"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTermRangeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    []byte
+		min, max []byte
+	}{
+		{
+			name:  "closed range should not return an error",
+			field: []byte("fruit"),
+			min:   []byte("apple"),
+			max:   []byte("banana"),
+		},
+		{
+			name:  "open range should not return an error",
+			field: []byte("fruit"),
+		},
+	}
+
+	rs := index.Readers{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := NewTermRangeQuery(test.field, test.min, test.max, true, false)
+			_, err := q.Searcher(rs)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTermRangeQueryEqual(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right search.Query
+		expected    bool
+	}{
+		{
+			name:     "same field and range",
+			left:     NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			right:    NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			expected: true,
+		},
+		{
+			name: "singular conjunction query",
+			left: NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			right: NewConjunctionQuery([]search.Query{
+				NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			}),
+			expected: true,
+		},
+		{
+			name:     "open bounds with different inclusivity",
+			left:     NewTermRangeQuery([]byte("fruit"), nil, nil, true, true),
+			right:    NewTermRangeQuery([]byte("fruit"), nil, nil, false, false),
+			expected: true,
+		},
+		{
+			name:     "different field",
+			left:     NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			right:    NewTermRangeQuery([]byte("food"), []byte("apple"), []byte("banana"), true, false),
+			expected: false,
+		},
+		{
+			name:     "different min",
+			left:     NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			right:    NewTermRangeQuery([]byte("fruit"), nil, []byte("banana"), true, false),
+			expected: false,
+		},
+		{
+			name:     "different max inclusivity",
+			left:     NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, false),
+			right:    NewTermRangeQuery([]byte("fruit"), []byte("apple"), []byte("banana"), true, true),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.left.Equal(test.right))
+		})
+	}
+}
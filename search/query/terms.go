@@ -0,0 +1,110 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/m3db/m3ninx/generated/proto/querypb"
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/searcher"
+)
+
+// TermsQuery finds documents which match any of a set of terms exactly.
+type TermsQuery struct {
+	field []byte
+	terms [][]byte
+}
+
+// NewTermsQuery constructs a new TermsQuery for the given field and terms. The terms
+// are sorted and deduplicated so each segment's terms can be walked in a single pass.
+func NewTermsQuery(field []byte, terms [][]byte) search.Query {
+	sorted := make([][]byte, len(terms))
+	copy(sorted, terms)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	deduped := sorted[:0]
+	for i, term := range sorted {
+		if i > 0 && bytes.Equal(term, sorted[i-1]) {
+			continue
+		}
+		deduped = append(deduped, term)
+	}
+
+	return &TermsQuery{
+		field: field,
+		terms: deduped,
+	}
+}
+
+// Searcher returns a searcher over the provided readers.
+func (q *TermsQuery) Searcher(rs index.Readers) (search.Searcher, error) {
+	if len(q.terms) == 0 {
+		return searcher.NewEmptySearcher(len(rs)), nil
+	}
+	return searcher.NewTermsSearcher(rs, q.field, q.terms), nil
+}
+
+// Equal reports whether q is equivalent to o.
+func (q *TermsQuery) Equal(o search.Query) bool {
+	o, ok := singular(o)
+	if !ok {
+		return false
+	}
+
+	inner, ok := o.(*TermsQuery)
+	if !ok {
+		return false
+	}
+
+	if !bytes.Equal(q.field, inner.field) || len(q.terms) != len(inner.terms) {
+		return false
+	}
+
+	for i := range q.terms {
+		if !bytes.Equal(q.terms[i], inner.terms[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToProto returns the Protobuf query struct corresponding to the terms query.
+func (q *TermsQuery) ToProto() *querypb.Query {
+	terms := querypb.TermsQuery{
+		Field: q.field,
+		Terms: q.terms,
+	}
+
+	return &querypb.Query{
+		Query: &querypb.Query_Terms{Terms: &terms},
+	}
+}
+
+func (q *TermsQuery) String() string {
+	return fmt.Sprintf("terms(%s, %s)", q.field, bytes.Join(q.terms, []byte(", ")))
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2018 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/m3db/m3ninx/index"
+	"github.com/m3db/m3ninx/search"
+	"github.com/m3db/m3ninx/search/searcher"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTermsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    []byte
+		terms    [][]byte
+		expected search.Searcher
+	}{
+		{
+			name:  "valid field and terms should not return an error",
+			field: []byte("fruit"),
+			terms: [][]byte{[]byte("banana"), []byte("apple")},
+		},
+		{
+			name:     "no terms should return an empty searcher",
+			field:    []byte("fruit"),
+			expected: searcher.NewEmptySearcher(0),
+		},
+	}
+
+	rs := index.Readers{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := NewTermsQuery(test.field, test.terms)
+			s, err := q.Searcher(rs)
+			require.NoError(t, err)
+			if test.expected != nil {
+				require.Equal(t, test.expected, s)
+			}
+		})
+	}
+}
+
+func TestTermsQuerySortsAndDedupesTerms(t *testing.T) {
+	terms := [][]byte{[]byte("banana"), []byte("apple"), []byte("banana")}
+	q := NewTermsQuery([]byte("fruit"), terms)
+
+	require.Equal(t, "terms(fruit, apple, banana)", q.String())
+
+	// The caller's slice should not be modified.
+	require.Equal(t, [][]byte{[]byte("banana"), []byte("apple"), []byte("banana")}, terms)
+}
+
+func TestTermsQueryEqual(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right search.Query
+		expected    bool
+	}{
+		{
+			name:     "same field and terms",
+			left:     NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right:    NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			expected: true,
+		},
+		{
+			name:     "same terms in a different order",
+			left:     NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right:    NewTermsQuery([]byte("fruit"), [][]byte{[]byte("banana"), []byte("apple")}),
+			expected: true,
+		},
+		{
+			name: "singular conjunction query",
+			left: NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right: NewConjunctionQuery([]search.Query{
+				NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			}),
+			expected: true,
+		},
+		{
+			name: "singular disjunction query",
+			left: NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right: NewDisjunctionQuery([]search.Query{
+				NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			}),
+			expected: true,
+		},
+		{
+			name:     "different field",
+			left:     NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right:    NewTermsQuery([]byte("food"), [][]byte{[]byte("apple"), []byte("banana")}),
+			expected: false,
+		},
+		{
+			name:     "different terms",
+			left:     NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple"), []byte("banana")}),
+			right:    NewTermsQuery([]byte("fruit"), [][]byte{[]byte("apple")}),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, test.left.Equal(test.right))
+		})
+	}
+}
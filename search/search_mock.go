@@ -59,16 +59,68 @@ func (m *MockExecutor) EXPECT() *MockExecutorMockRecorder {
 }
 
 // Execute mocks base method
-func (m *MockExecutor) Execute(q Query) (doc.Iterator, error) {
-	ret := m.ctrl.Call(m, "Execute", q)
-	ret0, _ := ret[0].(doc.Iterator)
+func (m *MockExecutor) Execute(q Query, opts ExecuteOptions) (ResultIterator, error) {
+	ret := m.ctrl.Call(m, "Execute", q, opts)
+	ret0, _ := ret[0].(ResultIterator)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Execute indicates an expected call of Execute
-func (mr *MockExecutorMockRecorder) Execute(q interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockExecutor)(nil).Execute), q)
+func (mr *MockExecutorMockRecorder) Execute(q, opts interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockExecutor)(nil).Execute), q, opts)
+}
+
+// Count mocks base method
+func (m *MockExecutor) Count(q Query) (int, error) {
+	ret := m.ctrl.Call(m, "Count", q)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count
+func (mr *MockExecutorMockRecorder) Count(q interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockExecutor)(nil).Count), q)
+}
+
+// AggregateTerms mocks base method
+func (m *MockExecutor) AggregateTerms(q Query, field []byte, opts AggregateOptions) (AggregateResults, error) {
+	ret := m.ctrl.Call(m, "AggregateTerms", q, field, opts)
+	ret0, _ := ret[0].(AggregateResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateTerms indicates an expected call of AggregateTerms
+func (mr *MockExecutorMockRecorder) AggregateTerms(q, field, opts interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateTerms", reflect.TypeOf((*MockExecutor)(nil).AggregateTerms), q, field, opts)
+}
+
+// AggregateFields mocks base method
+func (m *MockExecutor) AggregateFields(q Query, opts AggregateOptions) (AggregateResults, error) {
+	ret := m.ctrl.Call(m, "AggregateFields", q, opts)
+	ret0, _ := ret[0].(AggregateResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateFields indicates an expected call of AggregateFields
+func (mr *MockExecutorMockRecorder) AggregateFields(q, opts interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateFields", reflect.TypeOf((*MockExecutor)(nil).AggregateFields), q, opts)
+}
+
+// Facet mocks base method
+func (m *MockExecutor) Facet(q Query, req FacetRequest) (FacetResults, error) {
+	ret := m.ctrl.Call(m, "Facet", q, req)
+	ret0, _ := ret[0].(FacetResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Facet indicates an expected call of Facet
+func (mr *MockExecutorMockRecorder) Facet(q, req interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Facet", reflect.TypeOf((*MockExecutor)(nil).Facet), q, req)
 }
 
 // Close mocks base method
@@ -83,6 +135,101 @@ func (mr *MockExecutorMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExecutor)(nil).Close))
 }
 
+// MockResultIterator is a mock of ResultIterator interface
+type MockResultIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockResultIteratorMockRecorder
+}
+
+// MockResultIteratorMockRecorder is the mock recorder for MockResultIterator
+type MockResultIteratorMockRecorder struct {
+	mock *MockResultIterator
+}
+
+// NewMockResultIterator creates a new mock instance
+func NewMockResultIterator(ctrl *gomock.Controller) *MockResultIterator {
+	mock := &MockResultIterator{ctrl: ctrl}
+	mock.recorder = &MockResultIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResultIterator) EXPECT() *MockResultIteratorMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method
+func (m *MockResultIterator) Next() bool {
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next
+func (mr *MockResultIteratorMockRecorder) Next() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockResultIterator)(nil).Next))
+}
+
+// Current mocks base method
+func (m *MockResultIterator) Current() doc.Document {
+	ret := m.ctrl.Call(m, "Current")
+	ret0, _ := ret[0].(doc.Document)
+	return ret0
+}
+
+// Current indicates an expected call of Current
+func (mr *MockResultIteratorMockRecorder) Current() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Current", reflect.TypeOf((*MockResultIterator)(nil).Current))
+}
+
+// Err mocks base method
+func (m *MockResultIterator) Err() error {
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err
+func (mr *MockResultIteratorMockRecorder) Err() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockResultIterator)(nil).Err))
+}
+
+// Close mocks base method
+func (m *MockResultIterator) Close() error {
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockResultIteratorMockRecorder) Close() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockResultIterator)(nil).Close))
+}
+
+// LimitExceeded mocks base method
+func (m *MockResultIterator) LimitExceeded() bool {
+	ret := m.ctrl.Call(m, "LimitExceeded")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// LimitExceeded indicates an expected call of LimitExceeded
+func (mr *MockResultIteratorMockRecorder) LimitExceeded() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LimitExceeded", reflect.TypeOf((*MockResultIterator)(nil).LimitExceeded))
+}
+
+// Cursor mocks base method
+func (m *MockResultIterator) Cursor() Cursor {
+	ret := m.ctrl.Call(m, "Cursor")
+	ret0, _ := ret[0].(Cursor)
+	return ret0
+}
+
+// Cursor indicates an expected call of Cursor
+func (mr *MockResultIteratorMockRecorder) Cursor() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cursor", reflect.TypeOf((*MockResultIterator)(nil).Cursor))
+}
+
 // MockQuery is a mock of Query interface
 type MockQuery struct {
 	ctrl     *gomock.Controller
@@ -21,13 +21,33 @@
 package searcher
 
 import (
+	"sort"
+
 	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/search"
 )
 
+// unestimatedCost is the cost of a clause whose size cannot be estimated, so that it is
+// evaluated after all of the clauses which can be estimated.
+const unestimatedCost = int(^uint(0) >> 1)
+
+// estimatingSearcher is a Searcher which can estimate the size of its postings list for
+// the next Reader without retrieving it, and skip that Reader entirely if its postings
+// list turns out not to be needed.
+type estimatingSearcher interface {
+	search.Searcher
+
+	// Estimate returns an estimate of the number of documents matched in the next Reader,
+	// and false if it cannot be estimated without retrieving the postings list.
+	Estimate() (int, bool, error)
+
+	// Skip advances the searcher past the next Reader without retrieving its postings list.
+	Skip() bool
+}
+
 type conjunctionSearcher struct {
-	searchers  search.Searchers
-	negations  search.Searchers
+	searchers  []conjunctionClause
+	negations  []conjunctionClause
 	numReaders int
 
 	idx  int
@@ -36,7 +56,9 @@ type conjunctionSearcher struct {
 }
 
 // NewConjunctionSearcher returns a new Searcher which matches documents which match each
-// of the given searchers and none of the negations. It is not safe for concurrent access.
+// of the given searchers and none of the negations. The searchers are intersected in order
+// of increasing estimated size for each Reader and the remaining searchers are skipped once
+// the intersection is empty. It is not safe for concurrent access.
 func NewConjunctionSearcher(numReaders int, searchers, negations search.Searchers) (search.Searcher, error) {
 	if len(searchers) == 0 {
 		return nil, errEmptySearchers
@@ -50,8 +72,8 @@ func NewConjunctionSearcher(numReaders int, searchers, negations search.Searcher
 	}
 
 	return &conjunctionSearcher{
-		searchers:  searchers,
-		negations:  negations,
+		searchers:  newConjunctionClauses(searchers),
+		negations:  newConjunctionClauses(negations),
 		numReaders: numReaders,
 		idx:        -1,
 	}, nil
@@ -62,49 +84,72 @@ func (s *conjunctionSearcher) Next() bool {
 		return false
 	}
 
-	var pl postings.MutableList
 	s.idx++
-	for _, sr := range s.searchers {
-		if !sr.Next() {
-			err := sr.Err()
-			if err == nil {
-				err = errSearcherTooShort
+	for _, clauses := range [][]conjunctionClause{s.searchers, s.negations} {
+		for i := range clauses {
+			if err := clauses[i].plan(); err != nil {
+				s.err = err
+				return false
 			}
+		}
+	}
+
+	// Take the intersection in order of increasing size so that we only clone the smallest
+	// postings list and the intersection becomes empty as early as possible.
+	sort.SliceStable(s.searchers, func(i, j int) bool {
+		return s.searchers[i].cost < s.searchers[j].cost
+	})
+	// Take the set differences in order of decreasing size for the same reason.
+	sort.SliceStable(s.negations, func(i, j int) bool {
+		return s.negations[i].cost > s.negations[j].cost
+	})
+
+	var pl postings.MutableList
+	for i := range s.searchers {
+		c := &s.searchers[i]
+
+		// We can skip the remaining searchers if the intersected postings list is ever empty.
+		if pl != nil && pl.IsEmpty() {
+			if err := c.skip(); err != nil {
+				s.err = err
+				return false
+			}
+			continue
+		}
+
+		curr, err := c.evaluate()
+		if err != nil {
 			s.err = err
 			return false
 		}
-		curr := sr.Current()
 
-		// TODO: Sort the iterators so that we take the intersection in order of increasing size.
 		if pl == nil {
 			pl = curr.Clone()
-		} else {
-			pl.Intersect(curr)
+		} else if err := pl.Intersect(curr); err != nil {
+			s.err = err
+			return false
 		}
+	}
+
+	for i := range s.negations {
+		c := &s.negations[i]
 
-		// We can break early if the interescted postings list is ever empty.
 		if pl.IsEmpty() {
-			break
+			if err := c.skip(); err != nil {
+				s.err = err
+				return false
+			}
+			continue
 		}
-	}
 
-	for _, sr := range s.negations {
-		if !sr.Next() {
-			err := sr.Err()
-			if err == nil {
-				err = errSearcherTooShort
-			}
+		curr, err := c.evaluate()
+		if err != nil {
 			s.err = err
 			return false
 		}
-		curr := sr.Current()
-
-		// TODO: Sort the iterators so that we take the set differences in order of decreasing size.
-		pl.Difference(curr)
-
-		// We can break early if the interescted postings list is ever empty.
-		if pl.IsEmpty() {
-			break
+		if err := pl.Difference(curr); err != nil {
+			s.err = err
+			return false
 		}
 	}
 
@@ -124,3 +169,86 @@ func (s *conjunctionSearcher) Err() error {
 func (s *conjunctionSearcher) NumReaders() int {
 	return s.numReaders
 }
+
+// conjunctionClause is a searcher within a conjunction along with the cost of evaluating
+// it for the current Reader.
+type conjunctionClause struct {
+	searcher  search.Searcher
+	estimator estimatingSearcher
+
+	cost      int
+	evaluated bool
+	curr      postings.List
+}
+
+func newConjunctionClauses(searchers search.Searchers) []conjunctionClause {
+	clauses := make([]conjunctionClause, 0, len(searchers))
+	for _, sr := range searchers {
+		c := conjunctionClause{searcher: sr}
+		if est, ok := sr.(estimatingSearcher); ok {
+			c.estimator = est
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses
+}
+
+// plan computes the cost of the clause for the next Reader without evaluating it. Clauses
+// whose cost cannot be estimated are ordered after the rest.
+func (c *conjunctionClause) plan() error {
+	c.evaluated = false
+	c.curr = nil
+
+	if c.estimator == nil {
+		c.cost = unestimatedCost
+		return nil
+	}
+
+	cost, ok, err := c.estimator.Estimate()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cost = unestimatedCost
+	}
+	c.cost = cost
+	return nil
+}
+
+// evaluate returns the postings list of the clause for the current Reader.
+func (c *conjunctionClause) evaluate() (postings.List, error) {
+	if !c.evaluated {
+		if err := c.next(); err != nil {
+			return nil, err
+		}
+	}
+	return c.curr, nil
+}
+
+// skip advances the clause past the current Reader if it has not been evaluated already.
+// Searchers which cannot skip a Reader are advanced with Next and their postings list is
+// discarded.
+func (c *conjunctionClause) skip() error {
+	if c.evaluated {
+		return nil
+	}
+	if c.estimator == nil {
+		if !c.searcher.Next() {
+			return searcherErr(c.searcher)
+		}
+		return nil
+	}
+	if !c.estimator.Skip() {
+		return searcherErr(c.searcher)
+	}
+	return nil
+}
+
+func (c *conjunctionClause) next() error {
+	if !c.searcher.Next() {
+		return searcherErr(c.searcher)
+	}
+	c.curr = c.searcher.Current()
+	c.evaluated = true
+	return nil
+}
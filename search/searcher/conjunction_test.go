@@ -23,6 +23,7 @@ package searcher
 import (
 	"testing"
 
+	"github.com/m3db/m3ninx/index"
 	"github.com/m3db/m3ninx/postings"
 	"github.com/m3db/m3ninx/postings/roaring"
 	"github.com/m3db/m3ninx/search"
@@ -115,6 +116,150 @@ func TestConjunctionSearcher(t *testing.T) {
 	require.NoError(t, s.Err())
 }
 
+func TestConjunctionSearcherOrdering(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		field                 = []byte("fruit")
+		large, small, negated = []byte("apple"), []byte("banana"), []byte("cherry")
+	)
+
+	largePL := roaring.NewPostingsList()
+	largePL.AddRange(postings.ID(0), postings.ID(100))
+	smallPL := roaring.NewPostingsList()
+	smallPL.Insert(postings.ID(42))
+	smallPL.Insert(postings.ID(50))
+	negatedPL := roaring.NewPostingsList()
+	negatedPL.Insert(postings.ID(50))
+
+	firstReader := index.NewMockReader(mockCtrl)
+	secondReader := index.NewMockReader(mockCtrl)
+
+	// The searchers are estimated for each Reader before being evaluated.
+	firstReader.EXPECT().DocFrequency(field, large).Return(100, nil)
+	firstReader.EXPECT().DocFrequency(field, small).Return(2, nil)
+	firstReader.EXPECT().DocFrequency(field, negated).Return(1, nil)
+	secondReader.EXPECT().DocFrequency(field, large).Return(0, nil)
+	secondReader.EXPECT().DocFrequency(field, small).Return(2, nil)
+	secondReader.EXPECT().DocFrequency(field, negated).Return(1, nil)
+
+	// The searchers are evaluated in order of increasing size and the remaining searchers
+	// are skipped once the intersection is empty.
+	gomock.InOrder(
+		firstReader.EXPECT().MatchTerm(field, small).Return(smallPL, nil),
+		firstReader.EXPECT().MatchTerm(field, large).Return(largePL, nil),
+		firstReader.EXPECT().MatchTerm(field, negated).Return(negatedPL, nil),
+
+		secondReader.EXPECT().MatchTerm(field, large).Return(roaring.NewPostingsList(), nil),
+	)
+
+	var (
+		readers   = index.Readers{firstReader, secondReader}
+		searchers = search.Searchers{
+			NewTermSearcher(readers, field, large),
+			NewTermSearcher(readers, field, small),
+		}
+		negations = search.Searchers{NewTermSearcher(readers, field, negated)}
+	)
+
+	s, err := NewConjunctionSearcher(len(readers), searchers, negations)
+	require.NoError(t, err)
+
+	require.True(t, s.Next())
+	expected := roaring.NewPostingsList()
+	expected.Insert(postings.ID(42))
+	require.True(t, s.Current().Equal(expected))
+
+	require.True(t, s.Next())
+	require.True(t, s.Current().IsEmpty())
+
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+}
+
+func TestConjunctionSearcherSkipsUnestimatedSearchers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		field = []byte("fruit")
+		term  = []byte("apple")
+	)
+
+	pl := roaring.NewPostingsList()
+	pl.Insert(postings.ID(42))
+
+	firstReader := index.NewMockReader(mockCtrl)
+	secondReader := index.NewMockReader(mockCtrl)
+
+	firstReader.EXPECT().DocFrequency(field, term).Return(0, nil)
+	firstReader.EXPECT().MatchTerm(field, term).Return(roaring.NewPostingsList(), nil)
+	secondReader.EXPECT().DocFrequency(field, term).Return(1, nil)
+	secondReader.EXPECT().MatchTerm(field, term).Return(pl, nil)
+
+	// The unestimated searcher is evaluated after the term searcher, so its postings list
+	// is only retrieved for the second Reader where the intersection is not empty.
+	unestimated := search.NewMockSearcher(mockCtrl)
+	gomock.InOrder(
+		unestimated.EXPECT().NumReaders().Return(2),
+		unestimated.EXPECT().Next().Return(true),
+		unestimated.EXPECT().Next().Return(true),
+		unestimated.EXPECT().Current().Return(pl),
+	)
+
+	var (
+		readers   = index.Readers{firstReader, secondReader}
+		searchers = search.Searchers{unestimated, NewTermSearcher(readers, field, term)}
+	)
+
+	s, err := NewConjunctionSearcher(len(readers), searchers, nil)
+	require.NoError(t, err)
+
+	require.True(t, s.Next())
+	require.True(t, s.Current().IsEmpty())
+
+	require.True(t, s.Next())
+	require.True(t, s.Current().Equal(pl))
+
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+}
+
+func TestConjunctionSearcherDocFrequencyUnavailable(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var (
+		field          = []byte("fruit")
+		known, unknown = []byte("apple"), []byte("banana")
+	)
+
+	// The searcher whose document frequency is unavailable is ordered last and skipped
+	// without retrieving its postings list once the intersection is empty.
+	r := index.NewMockReader(mockCtrl)
+	r.EXPECT().DocFrequency(field, unknown).Return(0, index.ErrDocFrequencyUnavailable)
+	r.EXPECT().DocFrequency(field, known).Return(0, nil)
+	r.EXPECT().MatchTerm(field, known).Return(roaring.NewPostingsList(), nil)
+
+	var (
+		readers   = index.Readers{r}
+		searchers = search.Searchers{
+			NewTermSearcher(readers, field, unknown),
+			NewTermSearcher(readers, field, known),
+		}
+	)
+
+	s, err := NewConjunctionSearcher(len(readers), searchers, nil)
+	require.NoError(t, err)
+
+	require.True(t, s.Next())
+	require.True(t, s.Current().IsEmpty())
+
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+}
+
 func TestConjunctionSearcherError(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -41,3 +41,11 @@ func validateSearchers(numReaders int, searchers search.Searchers) error {
 	}
 	return nil
 }
+
+// searcherErr returns the error of a searcher which unexpectedly ran out of postings lists.
+func searcherErr(s search.Searcher) error {
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return errSearcherTooShort
+}
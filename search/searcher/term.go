@@ -63,6 +63,33 @@ func (s *termSearcher) Next() bool {
 	return true
 }
 
+func (s *termSearcher) Estimate() (int, bool, error) {
+	if s.idx == len(s.readers)-1 {
+		return 0, false, errSearcherTooShort
+	}
+
+	r := s.readers[s.idx+1]
+	freq, err := r.DocFrequency(s.field, s.term)
+	if err == index.ErrDocFrequencyUnavailable {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return freq, true, nil
+}
+
+func (s *termSearcher) Skip() bool {
+	if s.err != nil || s.idx == len(s.readers)-1 {
+		return false
+	}
+
+	s.idx++
+	s.curr = nil
+
+	return true
+}
+
 func (s *termSearcher) Current() postings.List {
 	return s.curr
 }
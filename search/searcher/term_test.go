@@ -74,3 +74,47 @@ func TestTermSearcher(t *testing.T) {
 	require.False(t, s.Next())
 	require.NoError(t, s.Err())
 }
+
+func TestTermSearcherEstimateAndSkip(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	field, term := []byte("fruit"), []byte("apple")
+
+	firstReader := index.NewMockReader(mockCtrl)
+	secondPL := roaring.NewPostingsList()
+	secondPL.Insert(postings.ID(57))
+	secondReader := index.NewMockReader(mockCtrl)
+
+	gomock.InOrder(
+		// Estimate the first reader and then skip it.
+		firstReader.EXPECT().DocFrequency(field, term).Return(2, nil),
+
+		// Estimate the second reader and then query it.
+		secondReader.EXPECT().DocFrequency(field, term).Return(1, nil),
+		secondReader.EXPECT().MatchTerm(field, term).Return(secondPL, nil),
+	)
+
+	readers := []index.Reader{firstReader, secondReader}
+
+	s := NewTermSearcher(readers, field, term).(estimatingSearcher)
+
+	freq, ok, err := s.Estimate()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, freq)
+	require.True(t, s.Skip())
+
+	freq, ok, err = s.Estimate()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, freq)
+	require.True(t, s.Next())
+	require.True(t, s.Current().Equal(secondPL))
+
+	_, _, err = s.Estimate()
+	require.Error(t, err)
+	require.False(t, s.Skip())
+	require.False(t, s.Next())
+	require.NoError(t, s.Err())
+}
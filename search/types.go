@@ -32,12 +32,114 @@ import (
 // Executor is responsible for executing queries over a snapshot.
 type Executor interface {
 	// Execute executes a query over the Executor's snapshot.
-	Execute(q Query) (doc.Iterator, error)
+	Execute(q Query, opts ExecuteOptions) (ResultIterator, error)
+
+	// Count returns the number of documents matching a query over the Executor's snapshot
+	// without retrieving the documents themselves.
+	Count(q Query) (int, error)
+
+	// AggregateTerms returns the distinct terms of the given field among the documents
+	// matching a query over the Executor's snapshot.
+	AggregateTerms(q Query, field []byte, opts AggregateOptions) (AggregateResults, error)
+
+	// AggregateFields returns the distinct field names among the documents matching a
+	// query over the Executor's snapshot. The reserved ID field is excluded.
+	AggregateFields(q Query, opts AggregateOptions) (AggregateResults, error)
+
+	// Facet returns the terms with the most matching documents for each of the requested
+	// fields among the documents matching a query over the Executor's snapshot.
+	Facet(q Query, req FacetRequest) (FacetResults, error)
 
 	// Close closes the iterator.
 	Close() error
 }
 
+// ExecuteOptions are options for executing a query.
+type ExecuteOptions struct {
+	// Limit is the maximum number of documents to return. A limit of zero means the
+	// number of documents returned is unbounded.
+	Limit int
+
+	// Cursor resumes iteration from the position it was created at. A nil cursor starts
+	// from the beginning of the results.
+	Cursor Cursor
+}
+
+// Cursor is an opaque, serializable position within the results of a query. A cursor
+// is only valid for the snapshot it was created from.
+type Cursor []byte
+
+// AggregateOptions are options for aggregating the terms or field names of the documents
+// matching a query.
+type AggregateOptions struct {
+	// Counts indicates whether to return the number of matching documents which contain
+	// each term.
+	Counts bool
+
+	// TermLimit is the maximum number of distinct terms to return. A limit of zero means
+	// the number of terms returned is unbounded.
+	TermLimit int
+}
+
+// AggregateResults are the distinct terms or field names of the documents matching a
+// query.
+type AggregateResults struct {
+	// Terms are the distinct terms in ascending order.
+	Terms []AggregateTerm
+
+	// LimitExceeded is whether more distinct terms matched than the term limit.
+	LimitExceeded bool
+}
+
+// AggregateTerm is a distinct term along with the number of matching documents which
+// contain it, if counts were requested.
+type AggregateTerm struct {
+	Term  []byte
+	Count int
+}
+
+// FacetRequest is a request for the term counts of several fields.
+type FacetRequest struct {
+	// Fields are the fields to compute term counts for.
+	Fields [][]byte
+
+	// Size is the maximum number of terms to return for each field. A size of zero means
+	// that all of the terms are returned.
+	Size int
+}
+
+// FacetResults are the term counts for each of the requested fields.
+type FacetResults struct {
+	// Facets are the term counts for each field in the order the fields were requested.
+	Facets []Facet
+}
+
+// Facet is the term counts for a field.
+type Facet struct {
+	Field []byte
+
+	// Terms are the terms with the most matching documents in descending order of count,
+	// with ties broken by ascending term order.
+	Terms []AggregateTerm
+
+	// LimitExceeded is whether more distinct terms matched than the requested size.
+	LimitExceeded bool
+}
+
+// ResultIterator is an iterator over the documents matched by a query.
+type ResultIterator interface {
+	doc.Iterator
+
+	// LimitExceeded returns whether iteration stopped because the document limit was
+	// reached while more documents matched the query. It is only valid to call
+	// LimitExceeded once Next has returned false.
+	LimitExceeded() bool
+
+	// Cursor returns a cursor positioned after the current document which can be passed
+	// to a subsequent call to Execute to resume iteration.
+	Cursor() Cursor
+}
+
 // Query is a search query for documents.
 type Query interface {
 	fmt.Stringer